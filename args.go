@@ -0,0 +1,164 @@
+package envflag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// argValue is the value of an option in command line arguments.
+type argValue struct {
+	value string
+	// alias is the deprecated name used to set the value, if any.
+	alias string
+}
+
+type argSource struct {
+	values map[string]argValue
+}
+
+// argOption describes the parameter an option name refers to.
+type argOption struct {
+	p     Parameter
+	alias bool
+}
+
+// Args parses GNU-style command line arguments for the parameters in m
+// and retrieves a source providing their values and the remaining
+// non-option arguments. It is an alternative to Flags.
+//
+// Long options are the flag names of parameters and their deprecated
+// flag names, with values given as "--name=value" or "--name value".
+// Parameters tagged with `short:"n"` are also set by the short option "-n"
+// followed by the value, either as "-nvalue" or "-n value".
+// Short options are single characters, other `short` tags are errors.
+// Boolean options do not take separate values. They are set by "--name"
+// and can be negated by "--no-name". Short boolean options can be
+// bundled, "-abc" is the same as "-a -b -c".
+//
+// Options and non-option arguments can be mixed. All arguments after "--"
+// are non-option arguments, "-" on its own is a non-option argument.
+// If an option is given multiple times, the last value is used
+// except for lists, which are joined.
+//
+// Parameters are not modified. Values are only validated and set by Load.
+func Args(m Module, args []string) (Source, []string, error) {
+	long := make(map[string]argOption)
+	short := make(map[rune]argOption)
+	var errs errslice
+	eachParameter(m, func(p Parameter) {
+		if _, found := long[p.FlagName()]; !found {
+			long[p.FlagName()] = argOption{p: p}
+		}
+		for _, alias := range p.FlagAliases() {
+			if _, found := long[alias]; !found {
+				long[alias] = argOption{p: p, alias: true}
+			}
+		}
+		if s := p.Tag("short"); s != "" {
+			r, size := utf8.DecodeRuneInString(s)
+			if size != len(s) || r == utf8.RuneError {
+				errs = append(errs, errors.New(p.Path()+": short option "+strconv.Quote(s)+" is not a single character"))
+				return
+			}
+			if _, found := short[r]; !found {
+				short[r] = argOption{p: p}
+			}
+		}
+	})
+	if err := errs.Join(); err != nil {
+		return nil, nil, err
+	}
+	s := &argSource{values: make(map[string]argValue)}
+	set := func(opt argOption, name, value string) {
+		prev, given := s.values[opt.p.Path()]
+		v := argValue{value: repeat(opt.p, prev.value, given, value)}
+		if opt.alias {
+			v.alias = name
+		}
+		s.values[opt.p.Path()] = v
+	}
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return s, append(rest, args[i+1:]...), nil
+		case strings.HasPrefix(arg, "--"):
+			name, value := arg[2:], ""
+			eq := strings.IndexByte(name, '=')
+			if eq >= 0 {
+				name, value = name[:eq], name[eq+1:]
+			}
+			opt, found := long[name]
+			if !found {
+				pos := strings.TrimPrefix(name, "no-")
+				if neg, ok := long[pos]; ok && pos != name && isBool(neg.p) {
+					if eq >= 0 {
+						return nil, nil, errors.New("option --" + name + " does not take a value")
+					}
+					set(neg, pos, "false")
+					continue
+				}
+				return nil, nil, errors.New("unknown option --" + name)
+			}
+			switch {
+			case eq >= 0:
+			case isBool(opt.p):
+				value = "true"
+			case i+1 < len(args):
+				i++
+				value = args[i]
+			default:
+				return nil, nil, errors.New("option --" + name + " requires a value")
+			}
+			set(opt, name, value)
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			// bundled short options, the first taking a value ends the bundle
+			for j, size := 1, 0; j < len(arg); j += size {
+				var r rune
+				r, size = utf8.DecodeRuneInString(arg[j:])
+				name := arg[j : j+size]
+				opt, found := short[r]
+				if !found {
+					return nil, nil, errors.New("unknown option -" + name)
+				}
+				if isBool(opt.p) {
+					set(opt, name, "true")
+					continue
+				}
+				value := arg[j+size:]
+				if value == "" {
+					if i+1 == len(args) {
+						return nil, nil, errors.New("option -" + name + " requires a value")
+					}
+					i++
+					value = args[i]
+				}
+				set(opt, name, value)
+				break
+			}
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return s, rest, nil
+}
+
+func (s *argSource) Name() string {
+	return "args"
+}
+
+func (s *argSource) Lookup(p Parameter) (string, bool) {
+	v, ok := s.values[p.Path()]
+	return v.value, ok
+}
+
+func (s *argSource) LookupAlias(p Parameter) (str, alias, name string, found bool) {
+	v, ok := s.values[p.Path()]
+	if ok && v.alias != "" {
+		name = p.FlagName()
+	}
+	return v.value, v.alias, name, ok
+}
@@ -0,0 +1,120 @@
+package envflag
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArgs(t *testing.T) {
+	type config struct {
+		All     bool          `short:"a"`
+		Bare    bool          `short:"b"`
+		Color   bool          `short:"c"`
+		Output  string        `short:"o"`
+		Timeout time.Duration `deprecated:"wait"`
+		Cache   bool
+	}
+	tests := []struct {
+		args []string
+		want config
+		rest []string
+	}{
+		{
+			args: []string{"--output=out.txt", "in.txt", "--timeout", "1m"},
+			want: config{Output: "out.txt", Timeout: time.Minute, Cache: true},
+			rest: []string{"in.txt"},
+		},
+		{
+			args: []string{"-abc", "-o", "x", "--no-cache", "-", "--", "--all"},
+			want: config{All: true, Bare: true, Color: true, Output: "x"},
+			rest: []string{"-", "--all"},
+		},
+		{
+			args: []string{"-aofile", "-bo", "-x", "--wait=1s", "--bare=false"},
+			want: config{All: true, Output: "-x", Timeout: time.Second, Cache: true},
+		},
+	}
+	for _, test := range tests {
+		v := config{Cache: true}
+		m, err := Scan(&v, DeprecationHook(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		src, rest, err := Args(m, test.args)
+		if err != nil {
+			t.Errorf("%q: %s", test.args, err)
+			continue
+		}
+		if _, err := Load(m, src); err != nil {
+			t.Errorf("%q: %s", test.args, err)
+			continue
+		}
+		if v != test.want {
+			t.Errorf("%q: want %+v, got %+v", test.args, test.want, v)
+		}
+		if len(rest) != len(test.rest) {
+			t.Errorf("%q: want remaining arguments %q, got %q", test.args, test.rest, rest)
+			continue
+		}
+		for i := range rest {
+			if rest[i] != test.rest[i] {
+				t.Errorf("%q: want remaining arguments %q, got %q", test.args, test.rest, rest)
+				break
+			}
+		}
+	}
+}
+
+func TestArgsErrors(t *testing.T) {
+	v := struct {
+		Verbose bool `short:"v"`
+		Name    string
+	}{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"--unknown"},
+		{"-x"},
+		{"--name"},
+		{"-v", "--no-verbose=true"},
+		{"--no-name"},
+	} {
+		if _, _, err := Args(m, args); err == nil {
+			t.Errorf("%q: expected error", args)
+		}
+	}
+}
+
+func TestArgsShortRunes(t *testing.T) {
+	v := struct {
+		Verbose bool   `short:"ü"`
+		Name    string `short:"n"`
+	}{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, _, err := Args(m, []string{"-ünäme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(m, src); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Verbose || v.Name != "äme" {
+		t.Errorf("unexpected values %+v", v)
+	}
+
+	w := struct {
+		All bool `short:"ab"`
+	}{}
+	if m, err = Scan(&w); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = Args(m, nil)
+	if want := `All: short option "ab" is not a single character`; err == nil || err.Error() != want {
+		t.Errorf("want error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,182 @@
+package envflag
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/confactor/envflag/value"
+)
+
+// Completion generates shell completion scripts for the flags of a module.
+//
+// Values of parameters with an `enum:"a,b,c"` tag or a value implementing
+// value.Enumeration are completed with their names. Boolean and duration
+// parameters get suggestions of common values.
+type Completion struct {
+	// Program is the name of the completed command.
+	Program string
+
+	// FlagPrefix is prepended to flag names; "-" if empty.
+	FlagPrefix string
+}
+
+// durations are suggested for duration parameters.
+var durations = []string{"1s", "10s", "30s", "1m", "5m", "10m", "30m", "1h"}
+
+// choices retrieves suggested values for p.
+func choices(p Parameter) []string {
+	if names := enum(p); len(names) > 0 {
+		return names
+	}
+	if isBool(p) {
+		return []string{"true", "false"}
+	}
+	if valueType(p) == reflect.TypeOf(time.Duration(0)) {
+		return durations
+	}
+	return nil
+}
+
+// enum retrieves the allowed values of p from the `enum` tag
+// or from a value implementing value.Enumeration.
+func enum(p Parameter) []string {
+	if tag := p.Tag("enum"); tag != "" {
+		var names []string
+		for _, name := range strings.Split(tag, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	if e, ok := underlying(p).(value.Enumeration); ok {
+		return e.Names()
+	}
+	return nil
+}
+
+// isBool reports whether p is a boolean flag.
+func isBool(p Parameter) bool {
+	b, ok := underlying(p).(boolValue)
+	return ok && b.IsBoolFlag()
+}
+
+func (c *Completion) dash() string {
+	if c.FlagPrefix == "" {
+		return "-"
+	}
+	return c.FlagPrefix
+}
+
+// funcName retrieves a shell function name for the program.
+func (c *Completion) funcName() string {
+	name := []byte("_")
+	for _, b := range []byte(c.Program) {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+			name = append(name, b)
+		default:
+			name = append(name, '_')
+		}
+	}
+	return string(append(name, "_complete"...))
+}
+
+// Bash writes a bash completion script for m to w.
+//
+// Values are completed if they are separate arguments; boolean flags
+// do not take separate arguments and are not completed.
+func (c *Completion) Bash(w io.Writer, m Module) error {
+	fn, dash := c.funcName(), c.dash()
+	var flags []string
+	buf := []byte(fn + "() {\n" +
+		"\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n" +
+		"\tcase \"$prev\" in\n")
+	eachParameter(m, func(p Parameter) {
+		flags = append(flags, dash+p.FlagName())
+		vals := choices(p)
+		if len(vals) == 0 || isBool(p) {
+			return
+		}
+		buf = append(buf, "\t"+shellQuote(dash+p.FlagName())+")\n"...)
+		buf = append(buf, "\t\tCOMPREPLY=($(compgen -W "+shellQuote(strings.Join(vals, " "))+" -- \"$cur\"))\n"...)
+		buf = append(buf, "\t\treturn\n\t\t;;\n"...)
+	})
+	buf = append(buf, "\tesac\n"+
+		"\tCOMPREPLY=($(compgen -W "+shellQuote(strings.Join(flags, " "))+" -- \"$cur\"))\n"+
+		"}\n"+
+		"complete -F "+fn+" "+shellQuote(c.Program)+"\n"...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// Zsh writes a zsh completion script for m to w.
+//
+// Boolean flags are completed with an optional value following "=".
+func (c *Completion) Zsh(w io.Writer, m Module) error {
+	fn, dash := c.funcName(), c.dash()
+	buf := []byte("#compdef " + c.Program + "\n\n" + fn + "() {\n\t_arguments \\\n")
+	eachParameter(m, func(p Parameter) {
+		name := dash + p.FlagName()
+		desc := zshEscape(strings.Join(strings.Fields(p.Tag("usage")), " "))
+		vals := zshEscape(strings.Join(choices(p), " "))
+		var spec string
+		if isBool(p) {
+			spec = name + "=-[" + desc + "]::" + p.FlagName() + ":(" + vals + ")"
+		} else {
+			spec = name + "[" + desc + "]:" + p.FlagName() + ":"
+			if vals != "" {
+				spec += "(" + vals + ")"
+			}
+		}
+		buf = append(buf, "\t\t"+shellQuote(spec)+" \\\n"...)
+	})
+	buf = append(buf, "\t\t&& return 0\n}\n\n"+fn+" \"$@\"\n"...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// Fish writes a fish completion script for m to w.
+func (c *Completion) Fish(w io.Writer, m Module) error {
+	dash := c.dash()
+	opt := "-o"
+	if dash == "--" {
+		opt = "-l"
+	}
+	var buf []byte
+	eachParameter(m, func(p Parameter) {
+		buf = append(buf, "complete -c "+shellQuote(c.Program)+" "+opt+" "+shellQuote(p.FlagName())...)
+		if desc := strings.Join(strings.Fields(p.Tag("usage")), " "); desc != "" {
+			buf = append(buf, " -d "+shellQuote(desc)...)
+		}
+		if !isBool(p) {
+			// requires an argument
+			buf = append(buf, " -r"...)
+			if vals := choices(p); len(vals) > 0 {
+				buf = append(buf, " -f -a "+shellQuote(strings.Join(vals, " "))...)
+			}
+		}
+		buf = append(buf, '\n')
+	})
+	_, err := w.Write(buf)
+	return err
+}
+
+// shellQuote quotes s in single quotes for bash, zsh and fish.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// zshEscape escapes characters with a special meaning in _arguments specs.
+func zshEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`[`, `\[`,
+		`]`, `\]`,
+		`:`, `\:`,
+		`(`, `\(`,
+		`)`, `\)`,
+	).Replace(s)
+}
@@ -0,0 +1,65 @@
+package envflag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompletion(t *testing.T) {
+	v := struct {
+		Verbose bool
+		Mode    string `enum:"fast, safe" usage:"Processing mode [default: safe]."`
+		Timeout time.Duration
+		Name    string
+	}{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Completion{Program: "my-tool"}
+	for shell, test := range map[string]struct {
+		write func(*bytes.Buffer) error
+		want  []string
+	}{
+		"bash": {
+			func(buf *bytes.Buffer) error { return c.Bash(buf, m) },
+			[]string{
+				"_my_tool_complete() {",
+				"\t'-mode')\n\t\tCOMPREPLY=($(compgen -W 'fast safe' -- \"$cur\"))",
+				"\t'-timeout')\n\t\tCOMPREPLY=($(compgen -W '1s 10s 30s 1m 5m 10m 30m 1h' -- \"$cur\"))",
+				"compgen -W '-verbose -mode -timeout -name' -- \"$cur\"",
+				"complete -F _my_tool_complete 'my-tool'",
+			},
+		},
+		"zsh": {
+			func(buf *bytes.Buffer) error { return c.Zsh(buf, m) },
+			[]string{
+				"#compdef my-tool",
+				`'-verbose=-[]::verbose:(true false)' \`,
+				`'-mode[Processing mode \[default\: safe\].]:mode:(fast safe)' \`,
+				`'-name[]:name:' \`,
+			},
+		},
+		"fish": {
+			func(buf *bytes.Buffer) error { return c.Fish(buf, m) },
+			[]string{
+				"complete -c 'my-tool' -o 'verbose'\n",
+				"complete -c 'my-tool' -o 'mode' -d 'Processing mode [default: safe].' -r -f -a 'fast safe'\n",
+				"complete -c 'my-tool' -o 'name' -r\n",
+			},
+		},
+	} {
+		buf := &bytes.Buffer{}
+		if err := test.write(buf); err != nil {
+			t.Fatal(err)
+		}
+		script := buf.String()
+		for _, want := range test.want {
+			if !strings.Contains(script, want) {
+				t.Errorf("%s script does not contain %q:\n%s", shell, want, script)
+			}
+		}
+	}
+}
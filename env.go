@@ -0,0 +1,237 @@
+package envflag
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Lookuper provides environment variables.
+type Lookuper interface {
+	// LookupEnv retrieves the value of the variable named key
+	// and reports whether it is set.
+	LookupEnv(key string) (string, bool)
+
+	// Environ retrieves all variables in the form "key=value".
+	Environ() []string
+}
+
+type osEnv struct{}
+
+func (osEnv) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (osEnv) Environ() []string {
+	return os.Environ()
+}
+
+// OSEnv provides the environment variables of the process.
+var OSEnv Lookuper = osEnv{}
+
+type mapEnv map[string]string
+
+// FromMap retrieves a Lookuper providing the variables in env.
+func FromMap(env map[string]string) Lookuper {
+	return mapEnv(env)
+}
+
+// FromSlice retrieves a Lookuper providing variables in the form "key=value",
+// as retrieved by os.Environ. For duplicate keys, the last value is used.
+func FromSlice(env []string) Lookuper {
+	m := make(mapEnv, len(env))
+	for _, kv := range env {
+		key, val := kv, ""
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key, val = kv[:i], kv[i+1:]
+		}
+		m[key] = val
+	}
+	return m
+}
+
+func (m mapEnv) LookupEnv(key string) (string, bool) {
+	val, ok := m[key]
+	return val, ok
+}
+
+func (m mapEnv) Environ() []string {
+	env := make([]string, 0, len(m))
+	for key, val := range m {
+		env = append(env, key+"="+val)
+	}
+	sort.Strings(env)
+	return env
+}
+
+type envSource struct {
+	env    Lookuper
+	prefix string
+}
+
+// Env retrieves a source providing values from the environment variables
+// of the process. It is short for EnvFrom(OSEnv, prefix).
+func Env(prefix string) Source {
+	return EnvFrom(OSEnv, prefix)
+}
+
+// EnvFrom retrieves a source providing values from the variables in env.
+//
+// The name of a variable is the environment name of the parameter
+// with the prefix prepended. If it is not set, deprecated environment
+// variable names are used.
+func EnvFrom(env Lookuper, prefix string) Source {
+	return &envSource{env: env, prefix: prefix}
+}
+
+func (s *envSource) Name() string {
+	return "env"
+}
+
+func (s *envSource) Lookup(p Parameter) (string, bool) {
+	str, _, _, ok := s.LookupAlias(p)
+	return str, ok
+}
+
+func (s *envSource) LookupAlias(p Parameter) (str, alias, name string, found bool) {
+	if str, ok := s.env.LookupEnv(s.prefix + p.EnvName()); ok {
+		return str, "", "", true
+	}
+	for _, alias := range p.EnvAliases() {
+		if str, ok := s.env.LookupEnv(s.prefix + alias); ok {
+			return str, alias, p.EnvName(), true
+		}
+	}
+	return "", "", "", false
+}
+
+// UnknownEnv reports environment variables with a prefix
+// that do not belong to any parameter.
+type UnknownEnv struct {
+	// Names contains the sorted names of unknown variables.
+	Names []string
+
+	// Suggestions maps unknown names to similar names of parameters.
+	Suggestions map[string]string
+}
+
+func (e *UnknownEnv) Error() string {
+	msg := []byte("unknown environment variables: ")
+	for i, name := range e.Names {
+		if i > 0 {
+			msg = append(msg, ", "...)
+		}
+		msg = append(msg, name...)
+		if s, ok := e.Suggestions[name]; ok {
+			msg = append(msg, " (did you mean "...)
+			msg = append(msg, s...)
+			msg = append(msg, ")"...)
+		}
+	}
+	return string(msg)
+}
+
+var errEmptyPrefix = errors.New("prefix is empty")
+
+// CheckEnv reports environment variables of the process starting with prefix
+// that do not belong to a parameter in m. It is short for CheckEnvFrom(OSEnv, m, prefix).
+func CheckEnv(m Module, prefix string) error {
+	return CheckEnvFrom(OSEnv, m, prefix)
+}
+
+// CheckEnvFrom reports variables in env starting with prefix that do not
+// belong to a parameter in m. The error type is *UnknownEnv.
+//
+// For each unknown variable, a parameter variable with a similar name is suggested.
+// An empty prefix is an error, all variables of env would be reported.
+func CheckEnvFrom(env Lookuper, m Module, prefix string) error {
+	if prefix == "" {
+		return errEmptyPrefix
+	}
+	known := make(map[string]struct{})
+	eachParameter(m, func(p Parameter) {
+		known[prefix+p.EnvName()] = struct{}{}
+		for _, alias := range p.EnvAliases() {
+			known[prefix+alias] = struct{}{}
+		}
+	})
+	var unknown []string
+	for _, kv := range env.Environ() {
+		name := kv
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			name = kv[:i]
+		}
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if _, found := known[name]; !found {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	err := &UnknownEnv{
+		Names:       unknown,
+		Suggestions: make(map[string]string),
+	}
+	for _, name := range unknown {
+		if s, ok := suggest(name, known); ok {
+			err.Suggestions[name] = s
+		}
+	}
+	return err
+}
+
+// suggest retrieves the name in known most similar to name.
+// Names are only similar if at most a third of their characters differ.
+func suggest(name string, known map[string]struct{}) (string, bool) {
+	best, min := "", len(name)/3+1
+	for k := range known {
+		d := distance(name, k)
+		if d < min || d == min && k < best {
+			best, min = k, d
+		}
+	}
+	return best, best != ""
+}
+
+// distance calculates the edit distance of a and b.
+//
+// Insertions, deletions, substitutions and transpositions
+// of adjacent bytes count as one edit.
+func distance(a, b string) int {
+	// d[i][j] is the distance of a[:i] and b[:j]
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(v int, vs ...int) int {
+	for _, w := range vs {
+		if w < v {
+			v = w
+		}
+	}
+	return v
+}
@@ -0,0 +1,181 @@
+package envflag
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCheckEnv(t *testing.T) {
+	t.Parallel()
+	v := struct {
+		DB struct {
+			Host string
+			Port int
+		}
+	}{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"ENVFLAGTEST_DB_HOST": "localhost",
+		"OTHER":               "",
+	}
+	if err := CheckEnvFrom(FromMap(env), m, "ENVFLAGTEST_"); err != nil {
+		t.Fatalf("expected no unknown variables, got %s", err)
+	}
+	env["ENVFLAGTEST_DB_HOTS"] = "localhost"
+	env["ENVFLAGTEST_UNRELATED"] = ""
+	err = CheckEnvFrom(FromMap(env), m, "ENVFLAGTEST_")
+	unknown, ok := err.(*UnknownEnv)
+	if !ok {
+		t.Fatalf("expected *UnknownEnv, got %v", err)
+	}
+	if len(unknown.Names) != 2 ||
+		unknown.Names[0] != "ENVFLAGTEST_DB_HOTS" ||
+		unknown.Names[1] != "ENVFLAGTEST_UNRELATED" {
+		t.Errorf("unexpected unknown variables %v", unknown.Names)
+	}
+	if s := unknown.Suggestions["ENVFLAGTEST_DB_HOTS"]; s != "ENVFLAGTEST_DB_HOST" {
+		t.Errorf("expected suggestion ENVFLAGTEST_DB_HOST, got %q", s)
+	}
+	if s, ok := unknown.Suggestions["ENVFLAGTEST_UNRELATED"]; ok {
+		t.Errorf("expected no suggestion, got %q", s)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		d    int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"abc", "acb", 1},
+		{"abc", "abd", 1},
+		{"abc", "ab", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, test := range tests {
+		if d := distance(test.a, test.b); d != test.d {
+			t.Errorf("distance(%q, %q): want %d, got %d", test.a, test.b, test.d, d)
+		}
+	}
+}
+
+func TestEnvDeprecated(t *testing.T) {
+	t.Parallel()
+	v := struct {
+		Host string `deprecated:"HOSTNAME, host-name"`
+		Name string `env:"HOSTNAME"`
+	}{}
+	var used []string
+	m, err := ScanWarn(&v, DeprecationHook(func(p Parameter, alias, name string) {
+		used = append(used, p.Path(), alias, name)
+	}))
+	warn, ok := err.(*ScanWarnings)
+	if !ok || len(warn.Collisions) != 1 {
+		t.Fatalf("expected collision of deprecated name, got %v", err)
+	}
+	p, _ := m.Parameter("Host")
+	if aliases := p.Aliases(); len(aliases) != 2 || aliases[1] != "host-name" {
+		t.Fatalf("unexpected aliases %q", aliases)
+	}
+	if env, flags := p.EnvAliases(), p.FlagAliases(); len(env) != 1 || env[0] != "HOSTNAME" ||
+		len(flags) != 1 || flags[0] != "host-name" {
+		t.Fatalf("unexpected aliases %q and %q", env, flags)
+	}
+
+	env := FromMap(map[string]string{"ENVFLAGTEST_HOSTNAME": "localhost"})
+	if _, err := Plan(m, EnvFrom(env, "ENVFLAGTEST_")); err != nil {
+		t.Fatal(err)
+	}
+	if len(used) != 0 {
+		t.Errorf("Plan must not call the deprecation hook, got %q", used)
+	}
+	if _, err := Load(m, EnvFrom(env, "ENVFLAGTEST_")); err != nil {
+		t.Fatal(err)
+	}
+	if v.Host != "localhost" {
+		t.Errorf("expected value to be set through deprecated name")
+	}
+	if len(used) != 3 || used[0] != "Host" ||
+		used[1] != "HOSTNAME" || used[2] != "HOST" {
+		t.Errorf("unexpected deprecation hook arguments %q", used)
+	}
+	if err := CheckEnvFrom(env, m, "ENVFLAGTEST_"); err != nil {
+		t.Errorf("deprecated names must be known, got %s", err)
+	}
+}
+
+func TestEnvFrom(t *testing.T) {
+	t.Parallel()
+	v := struct {
+		Host string
+		Port int
+	}{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := FromSlice([]string{
+		"APP_HOST=old",
+		"APP_HOST=db=local",
+		"APP_PORT=5432",
+		"APP_PROT",
+	})
+	if want, got := "APP_HOST=db=local APP_PORT=5432 APP_PROT=", strings.Join(env.Environ(), " "); want != got {
+		t.Errorf("want environment %q, got %q", want, got)
+	}
+	if _, err := Load(m, EnvFrom(env, "APP_")); err != nil {
+		t.Fatal(err)
+	}
+	if v.Host != "db=local" || v.Port != 5432 {
+		t.Errorf("unexpected values %+v", v)
+	}
+	err = CheckEnvFrom(env, m, "APP_")
+	if unknown, ok := err.(*UnknownEnv); !ok || unknown.Suggestions["APP_PROT"] != "APP_PORT" {
+		t.Errorf("expected unknown variable APP_PROT, got %v", err)
+	}
+	if err := CheckEnvFrom(FromMap(map[string]string{"APP_HOST": ""}), m, "APP_"); err != nil {
+		t.Errorf("expected no unknown variables, got %s", err)
+	}
+	if err := CheckEnvFrom(env, m, ""); err != errEmptyPrefix {
+		t.Errorf("expected error on empty prefix, got %v", err)
+	}
+}
+
+func TestAliasKinds(t *testing.T) {
+	t.Parallel()
+	v := struct {
+		Name string `deprecated:"OLD_NAME,old-flag"`
+		Flag string `flag:"OLD_NAME"`
+		Env  string `env:"old-flag"`
+	}{}
+	m, err := ScanWarn(&v)
+	if err != nil {
+		t.Fatalf("aliases must only collide with names of their kind, got %v", err)
+	}
+	env := FromMap(map[string]string{"APP_old-flag": "x"})
+	if _, err := Load(m, EnvFrom(env, "APP_")); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "" || v.Env != "x" {
+		t.Errorf("flag aliases must not be environment variables, got %+v", v)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	Flags(fs, m)
+	if f := fs.Lookup("old-flag"); f == nil || f.Usage != "deprecated, use name" {
+		t.Errorf("expected deprecated flag old-flag")
+	}
+	if f := fs.Lookup("OLD_NAME"); f == nil || f.Usage == "deprecated, use name" {
+		t.Errorf("environment aliases must not be flags")
+	}
+	if _, _, err := Args(m, []string{"--OLD_NAME=x", "--old-flag=y"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
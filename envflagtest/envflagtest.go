@@ -0,0 +1,179 @@
+// Package envflagtest provides helpers for tests of configurations
+// scanned with envflag.
+package envflagtest
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/confactor/envflag"
+)
+
+// update reports whether golden files are written instead of compared.
+var update = new(bool)
+
+func init() {
+	// only test binaries get the flag, not all programs importing the package
+	if testing.Testing() {
+		flag.BoolVar(update, "envflagtest.update", false, "update golden files")
+	}
+}
+
+// Input contains the configuration sources used by Load.
+type Input struct {
+	// EnvPrefix is prepended to environment variable names.
+	EnvPrefix string
+
+	// Env contains environment variables.
+	Env map[string]string
+
+	// Args contains command line arguments parsed with the flag package.
+	Args []string
+
+	// Options are passed to envflag.Scan.
+	Options []envflag.Option
+}
+
+// Result is the outcome of Load.
+type Result struct {
+	// Module is the scanned configuration.
+	Module envflag.Module
+
+	// Changes contains the changes made by envflag.Load.
+	Changes []envflag.Change
+
+	// Sources maps the paths of parameters to the names of
+	// the sources providing their values.
+	Sources map[string]string
+
+	// Args contains the arguments remaining after parsing flags.
+	Args []string
+}
+
+// recorder records which parameters a source provided.
+type recorder struct {
+	envflag.Source
+	sources map[string]string
+}
+
+func (r *recorder) Lookup(p envflag.Parameter) (string, bool) {
+	str, ok := r.Source.Lookup(p)
+	if ok {
+		r.sources[p.Path()] = r.Name()
+	}
+	return str, ok
+}
+
+// recorderAlias records which parameters a source providing
+// values through deprecated names provided.
+type recorderAlias struct {
+	recorder
+}
+
+func (r *recorderAlias) LookupAlias(p envflag.Parameter) (str, alias, name string, found bool) {
+	str, alias, name, found = r.Source.(envflag.AliasSource).LookupAlias(p)
+	if found {
+		r.sources[p.Path()] = r.Name()
+	}
+	return str, alias, name, found
+}
+
+// record wraps src to record the parameters it provides in sources.
+func record(src envflag.Source, sources map[string]string) envflag.Source {
+	r := recorder{Source: src, sources: sources}
+	if _, ok := src.(envflag.AliasSource); ok {
+		return &recorderAlias{r}
+	}
+	return &r
+}
+
+// Load scans structptr and loads the values provided by in.
+// Flags override environment variables.
+//
+// The test fails immediately on errors.
+func Load(t testing.TB, structptr interface{}, in Input) *Result {
+	t.Helper()
+	m, err := envflag.Scan(structptr, in.Options...)
+	if err != nil {
+		t.Fatalf("scan failed: %s", err)
+	}
+	fs := flag.NewFlagSet(t.Name(), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	res := &Result{
+		Module:  m,
+		Sources: make(map[string]string),
+	}
+	env := record(envflag.EnvFrom(envflag.FromMap(in.Env), in.EnvPrefix), res.Sources)
+	flags := record(envflag.Flags(fs, m), res.Sources)
+	if err := fs.Parse(in.Args); err != nil {
+		t.Fatalf("parsing flags failed: %s", err)
+	}
+	res.Args = fs.Args()
+	// sources are consulted in order, the last
+	// recorded source of a parameter provided its value
+	if res.Changes, err = envflag.Load(m, env, flags); err != nil {
+		t.Fatalf("load failed: %s", err)
+	}
+	return res
+}
+
+// AssertSources checks that the parameters at the paths in want were provided
+// by the named sources, "env" for environment variables and "flag" for flags.
+// An empty name asserts that no source provided a value.
+func AssertSources(t testing.TB, res *Result, want map[string]string) {
+	t.Helper()
+	paths := make([]string, 0, len(want))
+	for path := range want {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if got := res.Sources[path]; got != want[path] {
+			t.Errorf("%s: want source %q, got %q", path, want[path], got)
+		}
+	}
+}
+
+// Dump retrieves a line "path=value" for each parameter in m, sorted by path.
+func Dump(m envflag.Module) []byte {
+	var lines []string
+	var walk func(m envflag.Module)
+	walk = func(m envflag.Module) {
+		for _, sub := range m.Modules() {
+			walk(sub)
+		}
+		for _, p := range m.Parameters() {
+			lines = append(lines, p.Path()+"="+p.String()+"\n")
+		}
+	}
+	walk(m)
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, ""))
+}
+
+// Golden compares the dump of m with the golden file at path.
+//
+// With the flag -envflagtest.update, the golden file is written instead.
+// The flag is only defined in test binaries.
+func Golden(t testing.TB, m envflag.Module, path string) {
+	t.Helper()
+	got := Dump(m)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("updating golden file failed: %s", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file failed: %s", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("module does not match golden file %s:\nwant\n%s\ngot\n%s", path, want, got)
+	}
+}
@@ -0,0 +1,42 @@
+package envflagtest
+
+import (
+	"testing"
+	"time"
+)
+
+type config struct {
+	Verbose bool
+	DB      struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	v := config{}
+	v.DB.Port = 5432
+	res := Load(t, &v, Input{
+		EnvPrefix: "APP_",
+		Env: map[string]string{
+			"APP_DB_HOST":    "db.local",
+			"APP_DB_TIMEOUT": "1s",
+		},
+		Args: []string{"-db-timeout", "1m", "-verbose", "cmd"},
+	})
+	if v.DB.Host != "db.local" || v.DB.Timeout != time.Minute || !v.Verbose {
+		t.Errorf("unexpected values %+v", v)
+	}
+	if len(res.Args) != 1 || res.Args[0] != "cmd" {
+		t.Errorf("unexpected remaining arguments %q", res.Args)
+	}
+	AssertSources(t, res, map[string]string{
+		"DB/Host":    "env",
+		"DB/Port":    "",
+		"DB/Timeout": "flag",
+		"Verbose":    "flag",
+	})
+	Golden(t, res.Module, "testdata/config.golden")
+}
@@ -1,6 +1,6 @@
 package envflag
 
-import "fmt"
+import "errors"
 
 type errslice []error
 
@@ -11,12 +11,17 @@ func (es errslice) String() string {
 	case 1:
 		return es[0].Error()
 	}
-	return fmt.Sprintf("%#v", es)
+	return es.Join().Error()
 }
 
+// Join retrieves an error wrapping all errors in es, nil if es is empty.
+// The messages are separated by newlines.
 func (es errslice) Join() error {
-	if len(es) == 0 {
+	switch len(es) {
+	case 0:
 		return nil
+	case 1:
+		return es[0]
 	}
-	return fmt.Errorf(es.String())
+	return errors.Join(es...)
 }
@@ -0,0 +1,201 @@
+package envflag
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Example generates example configurations containing all parameters
+// of a module with their default values.
+//
+// The text of the `usage` tag and markers for required parameters
+// are added as comments where the format supports them.
+// Defaults of parameters tagged with `secret:"true"` are left empty.
+type Example struct {
+	// EnvPrefix is prepended to environment variable names.
+	EnvPrefix string
+
+	// SchemaURL is referenced as "$schema" in JSON examples if set.
+	SchemaURL string
+}
+
+// exampleValue retrieves the default of p for an example configuration.
+func exampleValue(p Parameter) string {
+	if tagged(p, "secret") {
+		return ""
+	}
+	return p.Default()
+}
+
+// appendComments appends the description of p as comment lines starting with prefix.
+func appendComments(buf []byte, p Parameter, prefix string) []byte {
+	usage := strings.Join(strings.Fields(p.Tag("usage")), " ")
+	if usage != "" {
+		buf = appendWrapped(buf, usage, prefix+" ", 80)
+	}
+	if tagged(p, "required") {
+		buf = append(buf, prefix+" Required.\n"...)
+	}
+	return buf
+}
+
+// Env writes an example for environment variables in .env format to w.
+func (e *Example) Env(w io.Writer, m Module) error {
+	var buf []byte
+	eachParameter(m, func(p Parameter) {
+		if len(buf) > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = appendComments(buf, p, "#")
+		buf = append(buf, e.EnvPrefix+p.EnvName()+"="...)
+		buf = append(buf, envQuote(exampleValue(p))...)
+		buf = append(buf, '\n')
+	})
+	_, err := w.Write(buf)
+	return err
+}
+
+// envQuote quotes values containing whitespace, quotes, comments or escapes.
+func envQuote(s string) string {
+	if strings.ContainsAny(s, " \t\r\n\"'#\\$`") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// INI writes an example in INI format to w.
+//
+// Each module containing parameters is a section named by
+// the map keys and field names along its path, separated by dots.
+func (e *Example) INI(w io.Writer, m Module) error {
+	var buf []byte
+	eachModule(m, func(m Module) {
+		params := m.Parameters()
+		if len(params) == 0 {
+			return
+		}
+		if path := m.Path(); path != "" {
+			if len(buf) > 0 {
+				buf = append(buf, '\n')
+			}
+			buf = append(buf, "["+strings.Join(segments(path), ".")+"]\n"...)
+		}
+		for i, p := range params {
+			if i > 0 {
+				buf = append(buf, '\n')
+			}
+			buf = appendComments(buf, p, ";")
+			buf = append(buf, unescape(p.Name())+" = "...)
+			buf = append(buf, envQuote(exampleValue(p))...)
+			buf = append(buf, '\n')
+		}
+	})
+	_, err := w.Write(buf)
+	return err
+}
+
+// segments retrieves the map keys and field names along path.
+func segments(path string) []string {
+	var segs []string
+	start := 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			i++
+		case '/':
+			segs = append(segs, unescape(path[start:i]))
+			start = i + 1
+		}
+	}
+	return append(segs, unescape(path[start:]))
+}
+
+// JSON writes an example in JSON format to w.
+//
+// Modules are objects or, if they contain array or slice elements, arrays.
+// JSON does not support comments, descriptions are provided by Schema.
+func (e *Example) JSON(w io.Writer, m Module) error {
+	buf := []byte("{")
+	n := 0
+	if e.SchemaURL != "" {
+		buf = appendJSONKey(buf, "$schema", "  ")
+		buf = appendJSONString(buf, e.SchemaURL)
+		n++
+	}
+	buf = appendJSONMembers(buf, m, "  ", n)
+	buf = append(buf, "\n}\n"...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendJSONMembers appends the parameters and modules of m as members of
+// an object or elements of an array; n is the number of preceding members.
+func appendJSONMembers(buf []byte, m Module, indent string, n int) []byte {
+	mod, ok := m.(*module)
+	list := ok && mod.list
+	for _, p := range m.Parameters() {
+		if n > 0 {
+			buf = append(buf, ',')
+		}
+		n++
+		if list {
+			buf = append(buf, "\n"+indent...)
+		} else {
+			buf = appendJSONKey(buf, unescape(p.Name()), indent)
+		}
+		buf = appendJSONValue(buf, p)
+	}
+	for _, sub := range m.Modules() {
+		if n > 0 {
+			buf = append(buf, ',')
+		}
+		n++
+		if list {
+			buf = append(buf, "\n"+indent...)
+		} else {
+			buf = appendJSONKey(buf, unescape(sub.Name()), indent)
+		}
+		open, close := byte('{'), byte('}')
+		if s, ok := sub.(*module); ok && s.list {
+			open, close = '[', ']'
+		}
+		buf = append(buf, open)
+		if len(sub.Parameters())+len(sub.Modules()) > 0 {
+			buf = appendJSONMembers(buf, sub, indent+"  ", 0)
+			buf = append(buf, "\n"+indent...)
+		}
+		buf = append(buf, close)
+	}
+	return buf
+}
+
+func appendJSONKey(buf []byte, key, indent string) []byte {
+	buf = append(buf, "\n"+indent...)
+	buf = appendJSONString(buf, key)
+	return append(buf, ": "...)
+}
+
+func appendJSONString(buf []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(buf, b...)
+}
+
+// appendJSONValue appends the example value of p as a JSON value
+// of the type inferred by Schema.
+func appendJSONValue(buf []byte, p Parameter) []byte {
+	str := exampleValue(p)
+	typ, _ := typeSchema(registryOf(p), valueType(p))["type"].(string)
+	if v, ok := jsonValue(typ, str); ok && typ != "string" {
+		return append(buf, v.(json.RawMessage)...)
+	}
+	if v, ok := jsonCollection(p); ok && !tagged(p, "secret") {
+		return append(buf, v...)
+	}
+	if typ == "boolean" || typ == "integer" || typ == "number" || typ == "array" || typ == "object" {
+		// no valid default, e.g. for secrets
+		return append(buf, "null"...)
+	}
+	return appendJSONString(buf, str)
+}
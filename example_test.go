@@ -0,0 +1,216 @@
+package envflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type exampleTest struct {
+	Name    string `usage:"Name of the service."`
+	Debug   bool
+	Servers []struct {
+		Host string `required:"true"`
+		Port int
+	}
+	DB struct {
+		Password string `secret:"true"`
+		Retries  int
+	}
+}
+
+func newExampleTest(t *testing.T) Module {
+	v := exampleTest{Name: "my svc"}
+	v.Servers = make([]struct {
+		Host string `required:"true"`
+		Port int
+	}, 1)
+	v.Servers[0].Port = 80
+	v.DB.Password = "secret"
+	v.DB.Retries = 3
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestExampleEnv(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := &Example{EnvPrefix: "APP_"}
+	if err := e.Env(buf, newExampleTest(t)); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"# Required.",
+		"APP_SERVERS_0_HOST=",
+		"",
+		"APP_SERVERS_0_PORT=80",
+		"",
+		"APP_DB_PASSWORD=",
+		"",
+		"APP_DB_RETRIES=3",
+		"",
+		"# Name of the service.",
+		`APP_NAME="my svc"`,
+		"",
+		"APP_DEBUG=false",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("want example\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestExampleINI(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := &Example{}
+	if err := e.INI(buf, newExampleTest(t)); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"; Name of the service.",
+		`Name = "my svc"`,
+		"",
+		"Debug = false",
+		"",
+		"[Servers.0]",
+		"; Required.",
+		"Host = ",
+		"",
+		"Port = 80",
+		"",
+		"[DB]",
+		"Password = ",
+		"",
+		"Retries = 3",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("want example\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestExampleJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := &Example{SchemaURL: "config.schema.json"}
+	if err := e.JSON(buf, newExampleTest(t)); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$schema": "config.schema.json",
+  "Name": "my svc",
+  "Debug": false,
+  "Servers": [
+    {
+      "Host": "",
+      "Port": 80
+    }
+  ],
+  "DB": {
+    "Password": "",
+    "Retries": 3
+  }
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("want example\n%s\ngot\n%s", want, got)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("invalid JSON")
+	}
+}
+
+func TestExampleJSONList(t *testing.T) {
+	v := struct {
+		Hosts    []string
+		Timeouts []time.Duration
+		Ports    []int
+	}{
+		Hosts:    []string{"a", "b"},
+		Timeouts: []time.Duration{time.Second},
+	}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := (&Example{}).JSON(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "Hosts": ["a","b"],
+  "Timeouts": ["1s"],
+  "Ports": []
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("want example\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestExampleJSONMap(t *testing.T) {
+	v := struct {
+		Labels map[string]string
+		Limits map[string]int `sep:";"`
+	}{
+		Labels: map[string]string{"b": "2", "a": "1"},
+	}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(m.Parameters()); n != 2 {
+		t.Fatalf("expected 2 parameters, got %d", n)
+	}
+	if _, err := Load(m, Map("test", map[string]string{"Limits": "x=1;y=2"})); err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := (&Example{}).JSON(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "Labels": {"a":"1","b":"2"},
+  "Limits": {"x":1,"y":2}
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("want example\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestExampleEscapedKeys(t *testing.T) {
+	type Upstream struct {
+		URL string
+	}
+	v := struct {
+		Routes map[string]*Upstream
+	}{
+		Routes: map[string]*Upstream{"a/x": {URL: "u"}},
+	}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := (&Example{}).JSON(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]map[string]map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["Routes"]["a/x"]["URL"] != "u" {
+		t.Errorf("expected map key a/x, got\n%s", buf)
+	}
+	buf.Reset()
+	if err := (&Example{}).INI(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[Routes.a/x]\nURL = u\n"; buf.String() != want {
+		t.Errorf("want example\n%s\ngot\n%s", want, buf)
+	}
+}
@@ -0,0 +1,99 @@
+package envflag
+
+import (
+	"flag"
+
+	"github.com/confactor/envflag/value"
+)
+
+// flagValue records the value of a flag without modifying the parameter.
+type flagValue struct {
+	p     Parameter
+	name  string
+	value string
+	set   bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil || v.p == nil {
+		// zero value created by flag.PrintDefaults
+		return ""
+	}
+	return v.p.Default()
+}
+
+func (v *flagValue) Set(s string) error {
+	v.value, v.set = repeat(v.p, v.value, v.set, s), true
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.p != nil && isBool(v.p)
+}
+
+// repeat retrieves the representation of the value of p when it is given
+// as prev and then as s. Lists are joined, other values are replaced.
+func repeat(p Parameter, prev string, set bool, s string) string {
+	list, ok := underlying(p).(value.List)
+	if !ok || !set || prev == "" {
+		return s
+	}
+	if s == "" {
+		return prev
+	}
+	return prev + string(list.Separator()) + s
+}
+
+type flagSource struct {
+	// flags maps parameter paths to flags, the preferred name first.
+	flags map[string][]*flagValue
+}
+
+// Flags defines a flag in fs for each parameter in m, including flags
+// for deprecated flag names, and retrieves a source providing the flag values
+// once fs is parsed.
+//
+// Parameters are not modified when fs is parsed. Values are only
+// validated and set by Load. If a flag of a list is given multiple times,
+// the lists are joined.
+func Flags(fs *flag.FlagSet, m Module) Source {
+	s := &flagSource{flags: make(map[string][]*flagValue)}
+	eachParameter(m, func(p Parameter) {
+		usage := p.Tag("usage")
+		for i, name := range append([]string{p.FlagName()}, p.FlagAliases()...) {
+			if fs.Lookup(name) != nil {
+				// name collision, first definition wins
+				continue
+			}
+			v := &flagValue{p: p, name: name}
+			if i > 0 {
+				usage = "deprecated, use " + p.FlagName()
+			}
+			fs.Var(v, name, usage)
+			s.flags[p.Path()] = append(s.flags[p.Path()], v)
+		}
+	})
+	return s
+}
+
+func (s *flagSource) Name() string {
+	return "flag"
+}
+
+func (s *flagSource) Lookup(p Parameter) (string, bool) {
+	str, _, _, ok := s.LookupAlias(p)
+	return str, ok
+}
+
+func (s *flagSource) LookupAlias(p Parameter) (str, alias, name string, found bool) {
+	for i, v := range s.flags[p.Path()] {
+		if !v.set {
+			continue
+		}
+		if i > 0 {
+			return v.value, v.name, p.FlagName(), true
+		}
+		return v.value, "", "", true
+	}
+	return "", "", "", false
+}
@@ -0,0 +1,82 @@
+package envflag
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFlags(t *testing.T) {
+	v := struct {
+		Verbose bool
+		Timeout time.Duration `deprecated:"wait"`
+		Name    string
+	}{Name: "svc"}
+	deprecated := ""
+	m, err := Scan(&v, DeprecationHook(func(p Parameter, alias, name string) {
+		deprecated = alias + "," + name
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	src := Flags(fs, m)
+	if err := fs.Parse([]string{"-verbose", "-wait", "1m", "arg"}); err != nil {
+		t.Fatal(err)
+	}
+	if fs.NArg() != 1 {
+		t.Errorf("expected one remaining argument")
+	}
+	if v.Verbose {
+		t.Errorf("parsing flags must not modify values")
+	}
+
+	changes, err := Load(m, Map("defaults", map[string]string{"Name": "default"}), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Verbose || v.Timeout != time.Minute || v.Name != "default" {
+		t.Errorf("unexpected values %+v", v)
+	}
+	if deprecated != "wait,timeout" {
+		t.Errorf("expected use of deprecated flag to be reported")
+	}
+	if len(changes) != 3 || changes[0].Source != "flag" || changes[2].Source != "defaults" {
+		t.Errorf("unexpected changes %v", changes)
+	}
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	Flags(fs, m)
+	if err := fs.Parse([]string{"-unknown"}); err == nil {
+		t.Errorf("expected error on unknown flag")
+	}
+}
+
+func TestFlagsList(t *testing.T) {
+	v := struct {
+		Hosts []string
+		Name  string
+	}{Hosts: []string{"default"}}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	src := Flags(fs, m)
+	if err := fs.Parse([]string{"-hosts", "a,b", "-name", "x", "-hosts", "c", "-name", "y"}); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := Plan(m, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Hosts) != 1 {
+		t.Errorf("Plan must not modify values, got %q", v.Hosts)
+	}
+	if len(changes) != 2 || changes[0].New != "a,b,c" || changes[1].New != "y" {
+		t.Errorf("unexpected changes %v", changes)
+	}
+}
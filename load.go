@@ -0,0 +1,230 @@
+package envflag
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+
+	"github.com/confactor/envflag/value"
+)
+
+// Source provides string representations of parameter values.
+type Source interface {
+	// Name identifies the source in a Change.
+	Name() string
+
+	// Lookup retrieves the representation of the value for p
+	// and reports whether the source provides one.
+	Lookup(p Parameter) (string, bool)
+}
+
+// AliasSource is implemented by sources providing values
+// through deprecated names.
+type AliasSource interface {
+	Source
+
+	// LookupAlias is like Lookup. If the value is provided through
+	// a deprecated name, it also retrieves that alias and the name
+	// replacing it, both as listed by the parameter, without prefixes.
+	LookupAlias(p Parameter) (str, alias, name string, found bool)
+}
+
+// logDeprecation is the default deprecation hook,
+// it writes a warning with the standard logger.
+func logDeprecation(p Parameter, alias, name string) {
+	log.Printf("envflag: %s is deprecated, use %s", alias, name)
+}
+
+// Change describes the modification of a single parameter.
+type Change struct {
+	// Path of the modified parameter.
+	Path string
+
+	// Old is the representation of the value before the change.
+	Old string
+
+	// New is the representation of the value after the change.
+	New string
+
+	// Source is the name of the source providing the new value.
+	Source string
+}
+
+// Load sets all parameters in m from sources.
+//
+// Sources are consulted in order, a value provided by a later source
+// overrides those of earlier ones. Overridden values are validated, too.
+// Parameters tagged with `required:"true"` must be provided by a source.
+// If a value is set through a deprecated name, the hook set with the
+// option DeprecationHook is called.
+//
+// Load retrieves the changes in m. On errors, all valid values are still set.
+func Load(m Module, sources ...Source) ([]Change, error) {
+	return load(m, sources, true)
+}
+
+// Plan is a dry run of Load.
+//
+// It consults all sources and validates all their values, including
+// overridden ones, but m is not modified.
+// Plan retrieves the changes and errors Load would produce.
+func Plan(m Module, sources ...Source) ([]Change, error) {
+	return load(m, sources, false)
+}
+
+func load(m Module, sources []Source, apply bool) ([]Change, error) {
+	var (
+		changes []Change
+		errs    errslice
+	)
+	eachParameter(m, func(p Parameter) {
+		old := p.String()
+		found := lookup(p, sources)
+		if len(found) == 0 {
+			if tagged(p, "required") {
+				errs = append(errs, errors.New(p.Path()+": required but not set"))
+			}
+			return
+		}
+		// validate overridden values
+		for _, f := range found[:len(found)-1] {
+			dest, ok := scratch(p)
+			if !ok {
+				break
+			}
+			if err := dest.Set(f.str); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s (overridden by %s): %w",
+					p.Path(), f.src.Name(), found[len(found)-1].src.Name(), err))
+			}
+		}
+		f := found[len(found)-1]
+		dest := Value(p)
+		if !apply {
+			var ok bool
+			if dest, ok = scratch(p); !ok {
+				errs = append(errs, errors.New(p.Path()+": value can not be copied"))
+				return
+			}
+		}
+		if err := dest.Set(f.str); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", p.Path(), f.src.Name(), err))
+			return
+		}
+		if apply && f.alias != "" {
+			deprecation(p)(p, f.alias, f.name)
+		}
+		if val := dest.String(); val != old {
+			changes = append(changes, Change{
+				Path:   p.Path(),
+				Old:    old,
+				New:    val,
+				Source: f.src.Name(),
+			})
+		}
+	})
+	return changes, errs.Join()
+}
+
+// provided is a value provided by a source.
+type provided struct {
+	str string
+	src Source
+	// alias is the deprecated name replaced by name, if used.
+	alias, name string
+}
+
+// lookup retrieves the values for p from all sources providing one, in order.
+func lookup(p Parameter, sources []Source) []provided {
+	var found []provided
+	for _, src := range sources {
+		f := provided{src: src}
+		ok := false
+		if as, isAlias := src.(AliasSource); isAlias {
+			f.str, f.alias, f.name, ok = as.LookupAlias(p)
+		} else {
+			f.str, ok = src.Lookup(p)
+		}
+		if ok {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+// deprecation retrieves the deprecation hook of p.
+func deprecation(p Parameter) func(p Parameter, alias, name string) {
+	if param, ok := p.(*parameter); ok && param.cfg != nil {
+		return param.cfg.deprecation
+	}
+	return logDeprecation
+}
+
+// scratch retrieves a copy of the value of p that can be set
+// without modifying p.
+//
+// Values of scanned parameters are created again for a copy of their memory,
+// values referencing it are not shared with the copy.
+func scratch(p Parameter) (Value, bool) {
+	if param, ok := p.(*parameter); ok && param.ptr != nil {
+		src := reflect.ValueOf(param.ptr)
+		dest := reflect.New(src.Type().Elem())
+		dest.Elem().Set(src.Elem())
+		return param.valueOf(dest.Interface())
+	}
+	if c, ok := underlying(p).(value.Copier); ok {
+		return c.Copy(), true
+	}
+	src := reflect.ValueOf(underlying(p))
+	if src.Kind() != reflect.Ptr || src.IsNil() {
+		return nil, false
+	}
+	dest := reflect.New(src.Type().Elem())
+	dest.Elem().Set(src.Elem())
+	val, ok := dest.Interface().(Value)
+	return val, ok
+}
+
+// underlying retrieves the value wrapped by p.
+func underlying(p Parameter) interface{} {
+	if param, ok := p.(*parameter); ok {
+		return param.Value
+	}
+	return p
+}
+
+// tagged reports whether the boolean tag key of f is true.
+func tagged(f Field, key string) bool {
+	b, _ := strconv.ParseBool(f.Tag(key))
+	return b
+}
+
+// eachParameter calls fn for all parameters in m and its submodules.
+func eachParameter(m Module, fn func(p Parameter)) {
+	for _, sub := range m.Modules() {
+		eachParameter(sub, fn)
+	}
+	for _, p := range m.Parameters() {
+		fn(p)
+	}
+}
+
+type mapSource struct {
+	name   string
+	values map[string]string
+}
+
+// Map retrieves a source providing values by parameter path.
+func Map(name string, values map[string]string) Source {
+	return &mapSource{name: name, values: values}
+}
+
+func (s *mapSource) Name() string {
+	return s.name
+}
+
+func (s *mapSource) Lookup(p Parameter) (string, bool) {
+	str, ok := s.values[p.Path()]
+	return str, ok
+}
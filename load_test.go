@@ -0,0 +1,180 @@
+package envflag
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/confactor/envflag/value"
+)
+
+type loadTest struct {
+	Name    string
+	Timeout time.Duration
+	DB      struct {
+		Host string `required:"true"`
+		Port uint16
+	}
+}
+
+func TestPlanDoesNotModify(t *testing.T) {
+	v := loadTest{Name: "svc", Timeout: time.Second}
+	v.DB.Port = 5432
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := Map("defaults", map[string]string{
+		"Name":    "svc",
+		"DB/Host": "localhost",
+	})
+	override := Map("override", map[string]string{
+		"Timeout": "1m",
+		"DB/Host": "db.local",
+	})
+	changes, err := Plan(m, defaults, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{"DB/Host", "", "db.local", "override"},
+		{"Timeout", "1s", "1m0s", "override"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("want %d changes, got %v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("want change %v, got %v", want[i], changes[i])
+		}
+	}
+	if v.Timeout != time.Second || v.DB.Host != "" {
+		t.Errorf("Plan must not modify values: %+v", v)
+	}
+	if _, err := Load(m, defaults, override); err != nil {
+		t.Fatal(err)
+	}
+	if v.Timeout != time.Minute || v.DB.Host != "db.local" {
+		t.Errorf("Load must modify values: %+v", v)
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	v := loadTest{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Plan(m); err == nil {
+		t.Errorf("expected error on missing required value")
+	}
+	bad := Map("bad", map[string]string{
+		"DB/Host": "localhost",
+		"DB/Port": "65536",
+	})
+	if _, err := Plan(m, bad); err == nil {
+		t.Errorf("expected error on invalid value")
+	}
+	if v.DB.Host != "" {
+		t.Errorf("Plan must not modify values on error")
+	}
+}
+
+func TestLoadErrorMessages(t *testing.T) {
+	v := loadTest{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(m, Map("bad", map[string]string{"DB/Port": "x"}))
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	want := `DB/Host: required but not set
+DB/Port: bad: strconv.ParseUint: parsing "x": invalid syntax`
+	if msg := err.Error(); msg != want {
+		t.Errorf("want message\n%s\ngot\n%s", want, msg)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("errors of values must be wrapped")
+	}
+}
+
+func TestPlanValidatesOverridden(t *testing.T) {
+	v := loadTest{}
+	v.DB.Host = "localhost"
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := Map("a", map[string]string{"DB/Port": "bogus"})
+	b := Map("b", map[string]string{"DB/Port": "1"})
+	for _, fn := range []func(Module, ...Source) ([]Change, error){Plan, Load} {
+		_, err := fn(m, a, b)
+		if err == nil || !strings.Contains(err.Error(), "DB/Port: a (overridden by b)") {
+			t.Errorf("expected error on overridden invalid value, got %v", err)
+		}
+	}
+	if v.DB.Port != 1 {
+		t.Errorf("Load must still set the valid value, got %d", v.DB.Port)
+	}
+}
+
+// celsius is configured through a value wrapping a pointer.
+type celsius struct {
+	Degrees float64
+}
+
+type celsiusValue struct {
+	c *celsius
+}
+
+func (v celsiusValue) Get() interface{} { return *v.c }
+func (v celsiusValue) String() string   { return string(v.AppendTo(nil)) }
+func (v celsiusValue) AppendTo(dest []byte) []byte {
+	return strconv.AppendFloat(dest, v.c.Degrees, 'g', -1, 64)
+}
+func (v celsiusValue) Set(s string) error {
+	d, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		v.c.Degrees = d
+	}
+	return err
+}
+
+func TestPlanWrappedPointer(t *testing.T) {
+	v := struct {
+		Temp  celsius
+		Temps *celsius
+	}{Temp: celsius{20}}
+	r := value.NewRegistry()
+	r.Register(reflect.TypeOf(celsius{}), func(ptr interface{}) value.Value {
+		return celsiusValue{ptr.(*celsius)}
+	})
+	m, err := Scan(&v, Registry(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := Map("x", map[string]string{"Temp": "30", "Temps": "5"})
+	changes, err := Plan(m, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Temp.Degrees != 20 || v.Temps != nil {
+		t.Errorf("Plan must not modify values, got %+v", v)
+	}
+	if len(changes) != 2 || changes[0].Old != "20" || changes[0].New != "30" {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+	changes, err = Load(m, Map("a", map[string]string{"Temp": "25"}), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Old != "20" || v.Temp.Degrees != 30 || v.Temps.Degrees != 5 {
+		t.Errorf("unexpected changes %+v of %+v", changes, v)
+	}
+}
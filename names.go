@@ -0,0 +1,182 @@
+package envflag
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameMapper derives an external name from the field names in a path.
+type NameMapper func(path []string) string
+
+// Initialisms contains upper case words recognized when names are split into words.
+//
+// Sequences of upper case letters are split into initialisms if they
+// consist of initialisms only, e.g. "DBURL" is split into "DB" and "URL".
+// Initialisms should only be added during initialization.
+var Initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DB": true, "DNS": true, "EOF": true, "GUID": true, "HTML": true,
+	"HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true,
+	"SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true,
+	"UUID": true, "URI": true, "URL": true, "UTF8": true, "VM": true,
+	"XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// Words splits a name into words.
+//
+// Words start at upper case letters following lower case letters or digits
+// and at the last upper case letter preceding a lower case letter,
+// unless it is a plural "s" ending the word.
+// All other characters except letters and digits delimit words.
+// Cases are retained, e.g. "HTTPServer2Addr" is split into
+// "HTTP", "Server2" and "Addr", "UserIDs" into "User" and "IDs".
+func Words(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			words = appendWord(words, runes[start:i])
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(r) {
+			continue
+		}
+		prev := runes[i-1]
+		next := i+1 < len(runes) && unicode.IsLower(runes[i+1]) && !plural(runes, i+1)
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && next {
+			words = appendWord(words, runes[start:i])
+			start = i
+		}
+	}
+	return appendWord(words, runes[start:])
+}
+
+// plural reports whether the rune at i is an "s" ending a word.
+func plural(runes []rune, i int) bool {
+	return runes[i] == 's' && (i+1 == len(runes) || !unicode.IsLower(runes[i+1]))
+}
+
+// appendWord appends word to words and splits upper case initialisms.
+func appendWord(words []string, word []rune) []string {
+	if len(word) == 0 {
+		return words
+	}
+	w := string(word)
+	// plural initialisms keep their "s"
+	upper := strings.TrimSuffix(w, "s")
+	if upper != "" && strings.ToUpper(upper) == upper {
+		if split, ok := splitInitialisms(nil, upper); ok {
+			split[len(split)-1] += w[len(upper):]
+			return append(words, split...)
+		}
+	}
+	return append(words, w)
+}
+
+// splitInitialisms splits w into initialisms and reports whether it succeeded.
+// Longer initialisms are preferred.
+func splitInitialisms(dest []string, w string) ([]string, bool) {
+	if w == "" {
+		return dest, true
+	}
+	for end := len(w); end > 0; end-- {
+		if !Initialisms[w[:end]] {
+			continue
+		}
+		if split, ok := splitInitialisms(append(dest, w[:end]), w[end:]); ok {
+			return split, true
+		}
+	}
+	return dest, false
+}
+
+// mapWords creates a NameMapper joining the words of each field name with sep
+// and the fields in a path with nest.
+func mapWords(sep, nest string, word func(i int, w string) string) NameMapper {
+	if nest == "" {
+		nest = sep
+	}
+	return func(path []string) string {
+		var buf []byte
+		n := 0
+		for i, name := range path {
+			if i > 0 {
+				buf = append(buf, nest...)
+				if nest != "" {
+					// word numbering restarts in nested fields
+					n = 0
+				}
+			}
+			for j, w := range Words(name) {
+				if j > 0 {
+					buf = append(buf, sep...)
+				}
+				buf = append(buf, word(n, w)...)
+				n++
+			}
+		}
+		return string(buf)
+	}
+}
+
+func lowerWord(i int, w string) string {
+	return strings.ToLower(w)
+}
+
+func upperWord(i int, w string) string {
+	return strings.ToUpper(w)
+}
+
+func camelWord(i int, w string) string {
+	if i == 0 {
+		return strings.ToLower(w)
+	}
+	if u := strings.ToUpper(w); Initialisms[u] {
+		return u
+	}
+	r := []rune(strings.ToLower(w))
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// UpperSnake maps paths to upper case words delimited by underscores,
+// e.g. "HTTPServer/ReadTimeout" to "HTTP_SERVER_READ_TIMEOUT".
+//
+// Fields in a path are delimited by nest, "_" is used if nest is empty.
+func UpperSnake(nest string) NameMapper {
+	return mapWords("_", nest, upperWord)
+}
+
+// KebabCase maps paths to lower case words delimited by hyphens,
+// e.g. "HTTPServer/ReadTimeout" to "http-server-read-timeout".
+//
+// Fields in a path are delimited by nest, "-" is used if nest is empty.
+func KebabCase(nest string) NameMapper {
+	return mapWords("-", nest, lowerWord)
+}
+
+// DotCase maps paths to lower case words delimited by dots,
+// e.g. "HTTPServer/ReadTimeout" to "http.server.read.timeout".
+//
+// Fields in a path are delimited by nest, "." is used if nest is empty.
+func DotCase(nest string) NameMapper {
+	return mapWords(".", nest, lowerWord)
+}
+
+// LowerCamel maps paths to concatenated words starting with an upper case letter
+// except for the first one, e.g. "HTTPServer/ReadTimeout" to "httpServerReadTimeout".
+// Initialisms are upper case, e.g. "ServerURL" is mapped to "serverURL".
+//
+// Fields in a path are delimited by nest, each starts with a lower case letter.
+func LowerCamel(nest string) NameMapper {
+	return mapWords("", nest, camelWord)
+}
+
+var (
+	envNames  = UpperSnake("_")
+	flagNames = KebabCase("-")
+)
@@ -0,0 +1,55 @@
+package envflag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := map[string]string{
+		"":                "",
+		"Name":            "Name",
+		"HTTPServer":      "HTTP Server",
+		"DBURL":           "DB URL",
+		"HTTPS":           "HTTPS",
+		"IDENTITY":        "IDENTITY",
+		"ServerURL":       "Server URL",
+		"HTTPServer2Addr": "HTTP Server2 Addr",
+		"UTF8Reader":      "UTF8 Reader",
+		"lower_snake-x":   "lower snake x",
+		"x509Cert":        "x509 Cert",
+		"UserIDs":         "User IDs",
+		"URLs":            "URLs",
+		"DBURLs":          "DB URLs",
+		"IDsByName":       "IDs By Name",
+		"APIsStatus":      "APIs Status",
+		"HTTPSession":     "HTTP Session",
+	}
+	for name, want := range tests {
+		if got := strings.Join(Words(name), " "); got != want {
+			t.Errorf("Words(%q): want %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestNameMappers(t *testing.T) {
+	path := []string{"HTTPServer", "DBURL"}
+	tests := []struct {
+		mapper NameMapper
+		want   string
+	}{
+		{UpperSnake(""), "HTTP_SERVER_DB_URL"},
+		{UpperSnake("__"), "HTTP_SERVER__DB_URL"},
+		{KebabCase(""), "http-server-db-url"},
+		{KebabCase("."), "http-server.db-url"},
+		{DotCase(""), "http.server.db.url"},
+		{DotCase("/"), "http.server/db.url"},
+		{LowerCamel(""), "httpServerDBURL"},
+		{LowerCamel("."), "httpServer.dbURL"},
+	}
+	for i, test := range tests {
+		if got := test.mapper(path); got != test.want {
+			t.Errorf("mapper #%d: want %q, got %q", i, test.want, got)
+		}
+	}
+}
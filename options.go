@@ -0,0 +1,155 @@
+package envflag
+
+import "github.com/confactor/envflag/value"
+
+// Option configures Scan and ScanWarn.
+type Option func(*config)
+
+type config struct {
+	// tag keys overriding the external names of a parameter
+	envTag  string
+	flagTag string
+
+	// tag key of comma separated deprecated names
+	deprecatedTag string
+
+	// mappings from paths to external names
+	envNames  NameMapper
+	flagNames NameMapper
+
+	// maxDepth limits the path length; 0 is unlimited.
+	maxDepth int
+
+	// strict reports warnings as errors.
+	strict bool
+
+	// embedded includes embedded fields of unexported types.
+	embedded bool
+
+	// valueOf is consulted before registry.
+	valueOf func(ptr interface{}) (value.Value, bool)
+
+	// deprecation is called when deprecated names are used.
+	deprecation func(p Parameter, alias, name string)
+
+	// registry creates values of pointers.
+	registry *value.Registry
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		envTag:        "env",
+		flagTag:       "flag",
+		deprecatedTag: "deprecated",
+		envNames:      envNames,
+		flagNames:     flagNames,
+		deprecation:   logDeprecation,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.registry == nil {
+		cfg.registry = value.NewRegistry()
+	}
+	return cfg
+}
+
+// TagKeys sets the keys of the struct tags overriding the environment variable
+// and the flag name of a parameter. The defaults are "env" and "flag".
+// Empty keys are ignored.
+func TagKeys(env, flag string) Option {
+	return func(cfg *config) {
+		if env != "" {
+			cfg.envTag = env
+		}
+		if flag != "" {
+			cfg.flagTag = flag
+		}
+	}
+}
+
+// DeprecatedTag sets the key of the struct tag listing deprecated names
+// of a parameter, separated by commas. The default is "deprecated".
+// Names containing lower case letters or dashes are flag names,
+// other names are environment variable names, e.g. `deprecated:"OLD_NAME,old-flag"`.
+//
+// Deprecated names can still be used to set a parameter, see DeprecationHook.
+func DeprecatedTag(key string) Option {
+	return func(cfg *config) {
+		if key != "" {
+			cfg.deprecatedTag = key
+		}
+	}
+}
+
+// Names sets the strategies used to derive environment variable and
+// flag names from parameter paths.
+// A nil NameMapper keeps the current strategy.
+// The defaults are UpperSnake("_") and KebabCase("-").
+func Names(env, flag NameMapper) Option {
+	return func(cfg *config) {
+		if env != nil {
+			cfg.envNames = env
+		}
+		if flag != nil {
+			cfg.flagNames = flag
+		}
+	}
+}
+
+// MaxDepth limits the number of nested fields in a path.
+// Deeper fields are skipped. A depth of 0 is unlimited.
+func MaxDepth(depth int) Option {
+	return func(cfg *config) {
+		cfg.maxDepth = depth
+	}
+}
+
+// Strict turns warnings into errors.
+// Scan fails with *ScanWarnings if fields are skipped or duplicated.
+func Strict() Option {
+	return func(cfg *config) {
+		cfg.strict = true
+	}
+}
+
+// DeprecationHook sets a function called by Load when a source provides
+// the value of p through the deprecated name alias instead of name.
+// Names are passed as listed by the parameter, without prefixes or dashes.
+// It is not called by Plan.
+//
+// The default hook writes a warning with the standard logger.
+// A nil fn disables warnings.
+func DeprecationHook(fn func(p Parameter, alias, name string)) Option {
+	return func(cfg *config) {
+		if fn == nil {
+			fn = func(Parameter, string, string) {}
+		}
+		cfg.deprecation = fn
+	}
+}
+
+// EmbedUnexported includes embedded fields of unexported struct types.
+// Their exported fields are scanned as a module named after the type.
+func EmbedUnexported() Option {
+	return func(cfg *config) {
+		cfg.embedded = true
+	}
+}
+
+// ValueHook sets a function converting pointers to values.
+// It is consulted before value.ValueOf and registries.
+func ValueHook(valueOf func(ptr interface{}) (value.Value, bool)) Option {
+	return func(cfg *config) {
+		cfg.valueOf = valueOf
+	}
+}
+
+// Registry sets a registry of value types. It is consulted instead of
+// value.ValueOf, types registered with value.Register are still supported,
+// also by a zero value.Registry. Optionals of registered types are supported.
+func Registry(r *value.Registry) Option {
+	return func(cfg *config) {
+		cfg.registry = r
+	}
+}
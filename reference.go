@@ -0,0 +1,150 @@
+package envflag
+
+import (
+	"io"
+	"strings"
+)
+
+// Reference renders tables documenting all parameters of a module.
+//
+// Each parameter is described by its path, environment variable, flag,
+// type, default value and the text of the `usage` tag.
+// Defaults of parameters tagged with `secret:"true"` are hidden.
+type Reference struct {
+	// Title is the title of the document.
+	Title string
+
+	// Section is the manual section of a man page; "1" if empty.
+	Section string
+
+	// EnvPrefix is prepended to environment variable names.
+	EnvPrefix string
+
+	// FlagPrefix is prepended to flag names; "-" if empty.
+	FlagPrefix string
+}
+
+// referenceHeader contains the column titles of a reference table.
+var referenceHeader = []string{"Path", "Environment", "Flag", "Type", "Default", "Description"}
+
+// rows retrieves a table row for each parameter in m.
+func (r *Reference) rows(m Module) [][]string {
+	dash := r.FlagPrefix
+	if dash == "" {
+		dash = "-"
+	}
+	var rows [][]string
+	eachParameter(m, func(p Parameter) {
+		def := p.Default()
+		if tagged(p, "secret") && def != "" {
+			def = hidden
+		}
+		desc := strings.Join(strings.Fields(p.Tag("usage")), " ")
+		if tagged(p, "required") {
+			desc = strings.TrimSpace("Required. " + desc)
+		}
+		if aliases := p.Aliases(); len(aliases) > 0 {
+			desc = strings.TrimSpace(desc + " Deprecated names: " + strings.Join(aliases, ", ") + ".")
+		}
+		rows = append(rows, []string{
+			p.Path(),
+			r.EnvPrefix + p.EnvName(),
+			dash + p.FlagName(),
+			typeName(p),
+			def,
+			desc,
+		})
+	})
+	return rows
+}
+
+// Markdown writes the reference for m as a Markdown table to w.
+func (r *Reference) Markdown(w io.Writer, m Module) error {
+	var buf []byte
+	if r.Title != "" {
+		buf = append(buf, "# "+r.Title+"\n\n"...)
+	}
+	buf = appendMarkdownRow(buf, referenceHeader, false)
+	buf = append(buf, "|"...)
+	for range referenceHeader {
+		buf = append(buf, "---|"...)
+	}
+	buf = append(buf, '\n')
+	for _, row := range r.rows(m) {
+		buf = appendMarkdownRow(buf, row, true)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendMarkdownRow appends a table row. All but the last cell are
+// formatted as code if code is true.
+func appendMarkdownRow(buf []byte, cells []string, code bool) []byte {
+	buf = append(buf, '|')
+	for i, cell := range cells {
+		buf = append(buf, ' ')
+		if cell != "" {
+			cell = strings.Replace(cell, "|", `\|`, -1)
+			if code && i < len(cells)-1 {
+				cell = "`" + strings.Replace(cell, "`", "'", -1) + "`"
+			}
+			buf = append(buf, cell...)
+		}
+		buf = append(buf, " |"...)
+	}
+	return append(buf, '\n')
+}
+
+// Man writes the reference for m as a man page in roff format to w.
+// The table requires the tbl preprocessor.
+func (r *Reference) Man(w io.Writer, m Module) error {
+	section := r.Section
+	if section == "" {
+		section = "1"
+	}
+	title := r.Title
+	if title == "" {
+		title = "CONFIGURATION"
+	}
+	buf := []byte(".TH " + roffQuote(strings.ToUpper(title)) + " " + roffQuote(section) + "\n" +
+		".SH CONFIGURATION\n" +
+		".TS\n" +
+		"allbox tab(\t);\n" +
+		strings.Repeat("lb ", len(referenceHeader)-1) + "lb\n" +
+		strings.Repeat("l ", len(referenceHeader)-1) + "lx.\n")
+	buf = appendRoffRow(buf, referenceHeader)
+	for _, row := range r.rows(m) {
+		buf = appendRoffRow(buf, row)
+	}
+	buf = append(buf, ".TE\n"...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendRoffRow appends a tbl row with text blocks as cells.
+func appendRoffRow(buf []byte, cells []string) []byte {
+	for i, cell := range cells {
+		if i > 0 {
+			buf = append(buf, '\t')
+		}
+		buf = append(buf, "T{\n"...)
+		buf = append(buf, roffEscape(cell)...)
+		buf = append(buf, "\nT}"...)
+	}
+	return append(buf, '\n')
+}
+
+// roffEscape escapes text for use on a roff text line.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if s == "" || strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		// empty lines and control characters at the start of a line
+		s = `\&` + s
+	}
+	return s
+}
+
+// roffQuote quotes an argument of a roff request.
+func roffQuote(s string) string {
+	return `"` + strings.Replace(roffEscape(s), `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,66 @@
+package envflag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type referenceTest struct {
+	Name string `usage:"Name of the service | instance."`
+	DB   struct {
+		Host     string `required:"true" usage:"Database host."`
+		Password string `secret:"true" deprecated:"DB_PASS"`
+	}
+}
+
+func TestReferenceMarkdown(t *testing.T) {
+	v := referenceTest{Name: "svc"}
+	v.DB.Password = "secret"
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	r := &Reference{Title: "Settings", EnvPrefix: "APP_"}
+	if err := r.Markdown(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"# Settings",
+		"",
+		"| Path | Environment | Flag | Type | Default | Description |",
+		"|---|---|---|---|---|---|",
+		"| `DB/Host` | `APP_DB_HOST` | `-db-host` | `string` |  | Required. Database host. |",
+		"| `DB/Password` | `APP_DB_PASSWORD` | `-db-password` | `string` | `******` | Deprecated names: DB_PASS. |",
+		"| `Name` | `APP_NAME` | `-name` | `string` | `svc` | Name of the service \\| instance. |",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("want markdown\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestReferenceMan(t *testing.T) {
+	v := referenceTest{Name: ".svc"}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	r := &Reference{Title: "my-tool"}
+	if err := r.Man(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	man := buf.String()
+	for _, want := range []string{
+		".TH \"MY\\-TOOL\" \"1\"\n",
+		"T{\nDB/Host\nT}\tT{\nDB_HOST\nT}\tT{\n\\-db\\-host\nT}\tT{\nstring\nT}\tT{\n\\&\nT}",
+		"T{\n\\&.svc\nT}",
+		".TE\n",
+	} {
+		if !strings.Contains(man, want) {
+			t.Errorf("man page does not contain %q:\n%s", want, man)
+		}
+	}
+}
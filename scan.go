@@ -2,38 +2,61 @@ package envflag
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/confactor/envflag/value"
+	"github.com/confactor/envflag/walk"
 )
 
 // Scan takes a pointer to a struct and recursively traverses struct fields,
-// pointers and interface values to build a representation of all
-// modifiable values in the data.
+// elements of arrays, slices and maps, pointers and interface values
+// to build a representation of all modifiable values in the data.
 // If a memory destination is encountered more than once, only the first occurence
 // is contained in Module.
-func Scan(structptr interface{}) (Module, error) {
+// Nil pointers to types usable as parameters are parameters allocating
+// the value on Set; their values implement value.Unsettable.
+//
+// The behaviour of Scan can be adjusted with options.
+func Scan(structptr interface{}, opts ...Option) (Module, error) {
+	cfg := newConfig(opts)
+	if cfg.strict {
+		return scanWarn(cfg, structptr)
+	}
 	return scanStructPtr(
 		&scanguard{known: make(map[interface{}]struct{})},
+		cfg,
 		structptr,
 	)
 }
 
 // ScanWarn is a version of Scan that returns warnings as errors.
 //
-// If no error occured but values are encountered more than once or
-// struct fields are skipped, the error type is *ScanWarnings.
-func ScanWarn(structptr interface{}) (Module, error) {
+// If no error occured but values are encountered more than once,
+// struct fields are skipped or parameters share external names,
+// the error type is *ScanWarnings.
+// The module is only retrieved with warnings if Strict is not used.
+func ScanWarn(structptr interface{}, opts ...Option) (Module, error) {
+	return scanWarn(newConfig(opts), structptr)
+}
+
+func scanWarn(cfg *config, structptr interface{}) (Module, error) {
 	tracer := &scantracer{
 		pointers: make(map[interface{}][]string),
 	}
-	m, err := scanStructPtr(tracer, structptr)
+	m, err := scanStructPtr(tracer, cfg, structptr)
 	if err != nil {
 		return nil, err
 	}
-	warning := tracer.warning()
+	warning := tracer.warning(m)
 	if warning != nil {
+		if cfg.strict {
+			return nil, warning
+		}
 		return m, warning
 	}
 	return m, nil
@@ -81,119 +104,317 @@ func (s *scanguard) register(ptr interface{}) bool {
 
 func (s *scanguard) skip() {}
 
-func scanStructPtr(scan scanner, ptr interface{}) (*module, error) {
+// scanstate holds the state of a scan.
+type scanstate struct {
+	scan scanner
+	cfg  *config
+	c    *walk.Crawler
+	// errs collects invalid tags.
+	errs errslice
+	// items contains the element types of lists being scanned as items.
+	items map[reflect.Type]struct{}
+}
+
+func scanStructPtr(scan scanner, cfg *config, ptr interface{}) (*module, error) {
 	if ptr == nil {
 		return nil, errPtrNil
 	}
-	value := reflect.ValueOf(ptr)
-	if value.Kind() != reflect.Ptr {
+	c, ok := walk.NewCrawler(ptr)
+	if !ok || c.Kind() != reflect.Struct {
 		return nil, errNoStructPtr
 	}
-	// register inital valueto avoid cycles
+	// register inital value to avoid cycles
 	scan.register(ptr)
-	value = value.Elem()
-	if value.Kind() != reflect.Struct {
-		return nil, errNoStructPtr
-	}
 	mod := &module{}
-	mod.scanChildren(scan, value)
+	s := &scanstate{scan: scan, cfg: cfg, c: c}
+	mod.scanChildren(s)
+	if err := s.errs.Join(); err != nil {
+		return nil, err
+	}
 	return mod, nil
 }
 
-// scanChildren adds fields of a struct or elements of an array or a slice to mod.
-func (mod *module) scanChildren(scan scanner, src reflect.Value) (ok bool) {
-	switch src.Kind() {
-	case reflect.Array, reflect.Slice:
-		for i, max := 0, src.Len(); i < max; i++ {
-			name := strconv.FormatInt(int64(i), 10)
-			scan.enter(name)
-			f := &field{name: name}
-			ok := mod.scanValue(scan, f, src.Index(i))
-			if !ok {
-				scan.skip()
-			}
-			scan.leave(name)
+// scanChildren adds fields of a struct or elements of an array, a slice or
+// a map at the current node to mod.
+func (mod *module) scanChildren(s *scanstate) (ok bool) {
+	switch s.c.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Struct, reflect.Map:
+	default:
+		return false
+	}
+	depth := s.c.Depth()
+	for it := s.c.Iterator(); it.HasNext(); {
+		if !it.EnterNext() {
+			// map entry was removed
+			continue
 		}
-		return true
-	case reflect.Struct:
-		t := src.Type()
-		for i, max := 0, src.NumField(); i < max; i++ {
-			ft := t.Field(i)
-			scan.enter(ft.Name)
-			f := &field{name: ft.Name, tag: ft.Tag}
-			ok := mod.scanValue(scan, f, src.Field(i))
-			if !ok {
-				scan.skip()
-			}
-			scan.leave(ft.Name)
+		f := mod.child(s.c, depth)
+		s.scan.enter(f.name)
+		ok := mod.scanValue(s, f)
+		if !ok {
+			s.scan.skip()
 		}
-		return true
+		s.scan.leave(f.name)
+		s.c.ReturnTo(depth)
 	}
-	return false
+	return true
 }
 
-// scanValue adds a single value into a parameter or a module and adds it to mod.
-func (mod *module) scanValue(scan scanner, field *field, src reflect.Value) (ok bool) {
+// child creates the field for the node entered from depth.
+func (mod *module) child(c *walk.Crawler, depth int) *field {
+	name := string(c.AppendKey(nil, depth, false))
+	f := &field{
+		name:  name,
+		path:  mod.join(name),
+		names: append(mod.names[:len(mod.names):len(mod.names)], name),
+	}
+	if sf, ok := c.Key(depth).(reflect.StructField); ok {
+		f.tag = sf.Tag
+		f.embedded = sf.Anonymous
+		f.exported = sf.PkgPath == ""
+	} else {
+		f.exported = true
+	}
+	return f
+}
+
+// join retrieves the path of a child field of mod.
+func (mod *module) join(name string) string {
+	if mod.path == "" {
+		return name
+	}
+	return mod.path + "/" + name
+}
+
+// scanValue adds the current node as a parameter or a module to mod.
+func (mod *module) scanValue(s *scanstate, field *field) (ok bool) {
+	if s.cfg.maxDepth > 0 && len(field.names) > s.cfg.maxDepth {
+		// too deeply nested
+		return false
+	}
+	if !field.exported && !(field.embedded && s.cfg.embedded) {
+		// unexported field; exported fields of embedded structs
+		// with unexported types are only scanned on request
+		return false
+	}
 	// find pointer to innermost memory destination
 	registered := false
 	for {
-		switch src.Kind() {
-		case reflect.Ptr:
-			if scan.register(src.Interface()) {
-				// pointer is known
-				return false
+		switch kind := s.c.Kind(); kind {
+		case reflect.Ptr, reflect.Interface:
+			if kind == reflect.Ptr && s.c.Size() == 0 {
+				// nil pointer; parameter allocating its value on Set?
+				return mod.scanNilPointer(s, field)
 			}
-			registered = true
-			fallthrough
-		case reflect.Interface:
-			if src.IsNil() {
-				// pointer or interface value is nil
+			if !s.c.Enter(walk.Elem) {
+				// interface value is nil
 				return false
 			}
-			src = src.Elem()
+			if kind != reflect.Ptr {
+				continue
+			}
+			if ptr, ok := s.c.Pointer(); ok {
+				if s.scan.register(ptr) {
+					// pointer is known
+					return false
+				}
+				registered = true
+			}
 			continue
 		}
 		break
 	}
-	if !src.CanAddr() {
-		// no simple value; struct, array or slice wrapped in interface{}?
-		submod := &module{field: *field}
-		if submod.scanChildren(scan, src) {
-			mod.module = append(mod.module, submod)
-			return true
-		}
-		return false
-	}
-	addr := src.Addr()
-	if !addr.CanInterface() {
-		return false
+	ptr, ok := s.c.Pointer()
+	if !ok {
+		// no simple value; struct, array, slice or map wrapped in interface{}
+		// or embedded struct with an unexported type?
+		return mod.scanModule(s, field)
 	}
-	ptr := addr.Interface()
-	if !registered && scan.register(ptr) {
+	if !registered && s.scan.register(ptr) {
 		// pointer is known
 		return false
 	}
-	// check whether src can be used as a parameter
-	val, ok := ptr.(value.Value)
-	if !ok {
-		// not a Value; pointer to valid builtin type?
-		val, ok = value.ValueOf(ptr)
-	}
-	if ok {
-		// usable Getter; src is a parameter
-		param := &parameter{
-			field: *field,
-			Value: val,
+	// check whether the node can be used as a parameter
+	if val, ok := s.valueOf(field, ptr); ok {
+		// usable Getter; node is a parameter
+		param := newParameter(s.cfg, field, val)
+		param.ptr = ptr
+		param.valueOf = func(ptr interface{}) (value.Value, bool) {
+			val, ok, _ := valueOf(param.cfg, &param.field, ptr)
+			return val, ok
 		}
 		mod.param = append(mod.param, param)
 		return true
 	}
-	// not a parameter; struct, array or slice?
-	submod := &module{field: *field}
-	if submod.scanChildren(scan, src) {
+	// not a parameter; struct, array, slice or map?
+	return mod.scanModule(s, field)
+}
+
+// scanNilPointer adds the current node, a nil pointer, as a parameter to mod
+// if the type it points to can be used as a parameter.
+func (mod *module) scanNilPointer(s *scanstate, field *field) (ok bool) {
+	ptr, ok := s.c.Pointer()
+	if !ok || s.scan.register(ptr) {
+		// not addressable or known
+		return false
+	}
+	val, ok := value.PointerOf(ptr, func(ptr interface{}) (value.Value, bool) {
+		return s.valueOf(field, ptr)
+	})
+	if !ok {
+		return false
+	}
+	param := newParameter(s.cfg, field, val)
+	param.ptr = ptr
+	param.valueOf = func(ptr interface{}) (value.Value, bool) {
+		return value.PointerOf(ptr, func(ptr interface{}) (value.Value, bool) {
+			val, ok, _ := valueOf(param.cfg, &param.field, ptr)
+			return val, ok
+		})
+	}
+	mod.param = append(mod.param, param)
+	return true
+}
+
+// scanModule adds the current node as a module to mod.
+func (mod *module) scanModule(s *scanstate, field *field) (ok bool) {
+	kind := s.c.Kind()
+	submod := &module{
+		field: *field,
+		list:  kind == reflect.Array || kind == reflect.Slice,
+	}
+	if submod.list {
+		submod.item = submod.scanItem(s, s.c.ValueType().Elem())
+	}
+	if submod.scanChildren(s) {
 		mod.module = append(mod.module, submod)
 		return true
 	}
 	// unknown type
 	return false
 }
+
+// scanItem scans a zero element of type elem of the list mod.
+// The element is the only child of the retrieved module.
+// Pointers are allocated, recursive element types are not scanned.
+func (mod *module) scanItem(s *scanstate, elem reflect.Type) *module {
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if _, found := s.items[elem]; found {
+		return nil
+	}
+	items := map[reflect.Type]struct{}{elem: {}}
+	for t := range s.items {
+		items[t] = struct{}{}
+	}
+	c, _ := walk.NewCrawler(reflect.New(reflect.ArrayOf(1, elem)).Interface())
+	item := &module{field: mod.field, list: true}
+	// errors are reported for actual elements
+	item.scanChildren(&scanstate{
+		scan:  &scanguard{known: make(map[interface{}]struct{})},
+		cfg:   s.cfg,
+		c:     c,
+		items: items,
+	})
+	if len(item.module) == 0 && len(item.param) == 0 {
+		// e.g. a nil interface
+		return nil
+	}
+	return item
+}
+
+// valueOf retrieves a Value for ptr and records invalid tags.
+func (s *scanstate) valueOf(field *field, ptr interface{}) (value.Value, bool) {
+	val, ok, err := valueOf(s.cfg, field, ptr)
+	if err != nil {
+		s.errs = append(s.errs, err)
+	}
+	return val, ok
+}
+
+// valueOf retrieves a Value for ptr. Values are retrieved from the registry,
+// values like value.Optional create other values with it. Other types are
+// converted by the ValueHook or retrieved from the registry, too.
+//
+// The separator of slices and maps can be set with the tag `sep:";"`,
+// quoting of slices with `quote:"false"`. Times are configured with the tags
+// `layout:"2006-01-02"` and `tz:"Europe/Berlin"`, see value.TimeOf.
+// Unknown time zones are errors.
+func valueOf(cfg *config, field *field, ptr interface{}) (value.Value, bool, error) {
+	r := cfg.registry
+	if _, ok := ptr.(value.Value); ok {
+		val, ok := r.ValueOf(ptr)
+		return val, ok, nil
+	}
+	if cfg.valueOf != nil {
+		// custom conversion
+		if val, ok := cfg.valueOf(ptr); ok {
+			return val, true, nil
+		}
+	}
+	layout, tz := field.tag.Get("layout"), field.tag.Get("tz")
+	if t, ok := ptr.(*time.Time); ok && (layout != "" || tz != "") {
+		var loc *time.Location
+		if tz != "" {
+			var err error
+			if loc, err = time.LoadLocation(tz); err != nil {
+				return nil, false, fmt.Errorf("%s: tz: %w", field.path, err)
+			}
+		}
+		return value.TimeOf(t, layout, loc), true, nil
+	}
+	sep, quote := field.tag.Get("sep"), field.tag.Get("quote")
+	if sep != "" || quote != "" {
+		delim, _ := utf8.DecodeRuneInString(sep)
+		if sep == "" {
+			delim = ','
+		}
+		q, err := strconv.ParseBool(quote)
+		if val, ok := r.SliceOf(ptr, delim, q || err != nil); ok {
+			return val, true, nil
+		}
+		if val, ok := r.MapOf(ptr, delim); ok {
+			return val, true, nil
+		}
+	}
+	val, ok := r.ValueOf(ptr)
+	return val, ok, nil
+}
+
+// isFlagName reports whether the deprecated name alias is a flag name,
+// i.e. it contains lower case letters or dashes.
+// Other names are environment variable names.
+func isFlagName(alias string) bool {
+	return strings.ContainsRune(alias, '-') || strings.ToUpper(alias) != alias
+}
+
+// newParameter creates a parameter and derives its external names.
+func newParameter(cfg *config, field *field, val value.Value) *parameter {
+	param := &parameter{
+		field: *field,
+		Value: val,
+		env:   field.tag.Get(cfg.envTag),
+		flag:  field.tag.Get(cfg.flagTag),
+		def:   val.String(),
+		cfg:   cfg,
+	}
+	if param.env == "" {
+		param.env = cfg.envNames(field.names)
+	}
+	if param.flag == "" {
+		param.flag = cfg.flagNames(field.names)
+	}
+	for _, alias := range strings.Split(field.tag.Get(cfg.deprecatedTag), ",") {
+		if alias = strings.TrimSpace(alias); alias == "" {
+			continue
+		}
+		param.aliases = append(param.aliases, alias)
+		if isFlagName(alias) {
+			param.flagAliases = append(param.flagAliases, alias)
+		} else {
+			param.envAliases = append(param.envAliases, alias)
+		}
+	}
+	return param
+}
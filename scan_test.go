@@ -1,6 +1,13 @@
 package envflag
 
 import (
+	"log/slog"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -145,6 +152,39 @@ func TestScanEmbedded(t *testing.T) {
 	}
 }
 
+func TestScanMap(t *testing.T) {
+	type Upstream struct {
+		URL string
+	}
+	v := struct {
+		Upstreams map[string]*Upstream
+	}{
+		Upstreams: map[string]*Upstream{
+			"b":   {URL: "b.local"},
+			"a/x": {URL: "a.local"},
+		},
+	}
+	m, err := ScanWarn(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ups, ok := m.Module("Upstreams")
+	if !ok {
+		t.Fatalf("expected module for map")
+	}
+	mods := ups.Modules()
+	if len(mods) != 2 {
+		t.Fatalf("expected a module per map entry, got %d", len(mods))
+	}
+	want := []string{`Upstreams/a\/x/URL`, "Upstreams/b/URL"}
+	for i, mod := range mods {
+		ps := mod.Parameters()
+		if len(ps) != 1 || ps[0].Path() != want[i] {
+			t.Errorf("expected parameter %s", want[i])
+		}
+	}
+}
+
 func TestScanParameterDuplicate(t *testing.T) {
 	v := struct {
 		I interface{}
@@ -237,6 +277,7 @@ func TestScanSkipping(t *testing.T) {
 		*EmbeddedPtrNil
 		*EmbeddedPtrNotNil
 		PtrNil      *int
+		PtrNilMod   *struct{ I int }
 		PtrNotNil   *int
 		unexported  int
 		Unsupported interface{}
@@ -255,7 +296,7 @@ func TestScanSkipping(t *testing.T) {
 
 	skips := []string{
 		"EmbeddedPtrNil",
-		"PtrNil",
+		"PtrNilMod",
 		"unexported",
 		"Unsupported",
 	}
@@ -348,7 +389,7 @@ func TestScanTerminatesOnCycles(t *testing.T) {
 }
 
 func TestScanErrors(t *testing.T) {
-	for name, scan := range map[string]func(interface{}) (Module, error){
+	for name, scan := range map[string]func(interface{}, ...Option) (Module, error){
 		"Scan":     Scan,
 		"ScanWarn": ScanWarn,
 	} {
@@ -375,6 +416,42 @@ func TestScanErrors(t *testing.T) {
 	}
 }
 
+func TestScanCollisions(t *testing.T) {
+	v := struct {
+		A struct {
+			BC int
+		}
+		AB struct {
+			C int
+		}
+		D int `env:"ABC"`
+		E int `flag:"d"`
+	}{}
+	join := func(path []string) string {
+		return strings.ToUpper(strings.Join(Words(strings.Join(path, "")), "_"))
+	}
+	_, err := ScanWarn(&v, Names(join, nil))
+	warn, ok := err.(*ScanWarnings)
+	if !ok {
+		t.Fatalf("expected collision warnings, got %v", err)
+	}
+	want := [][]string{
+		{"A/BC", "AB/C", "D"},
+		{"D", "E"},
+	}
+	if len(warn.Collisions) != len(want) {
+		t.Fatalf("want collisions %v, got %v", want, warn.Collisions)
+	}
+	for i, group := range want {
+		if strings.Join(group, " ") != strings.Join(warn.Collisions[i], " ") {
+			t.Errorf("want collisions %v, got %v", group, warn.Collisions[i])
+		}
+	}
+	if _, err := Scan(&v, Names(join, nil), Strict()); err == nil {
+		t.Errorf("expected an error in strict mode")
+	}
+}
+
 func TestScanWarningMsg(t *testing.T) {
 	// ScanWarnings is not intended to be modified, just checking Error() here
 
@@ -416,6 +493,13 @@ func TestScanWarningMsg(t *testing.T) {
 	if msg := warn.Error(); msg == "" {
 		t.Fatalf("message expected on warnings")
 	}
+
+	warn.Duplicates = nil
+	warn.Skipped = nil
+	warn.Collisions = dups
+	if msg := warn.Error(); msg == "" {
+		t.Fatalf("message expected on warnings")
+	}
 }
 
 /*
@@ -438,3 +522,309 @@ func TestValueOfCustomValue(t *testing.T) {
 	}
 }
 */
+
+type embeddedUnexported struct {
+	I int
+}
+
+func TestScanOptions(t *testing.T) {
+	type Inner struct {
+		S string
+		D struct {
+			I int
+		}
+	}
+	v := struct {
+		embeddedUnexported
+		Inner Inner
+		N     int `env:"NUM" flag:"num"`
+		C     chan byte
+	}{}
+
+	if _, err := Scan(&v, Strict()); err == nil {
+		t.Errorf("expected an error in strict mode")
+	}
+
+	m, err := Scan(&v, EmbedUnexported(), MaxDepth(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Module("embeddedUnexported"); !ok {
+		t.Errorf("expected embedded struct of unexported type")
+	}
+	if inner, ok := m.Module("Inner"); !ok {
+		t.Errorf("expected inner module")
+	} else if d, ok := inner.Module("D"); !ok || len(d.Parameters()) != 0 {
+		t.Errorf("expected parameters exceeding max depth to be skipped")
+	}
+	p, ok := m.Parameter("N")
+	if !ok {
+		t.Fatalf("expected parameter N")
+	}
+	if p.EnvName() != "NUM" || p.FlagName() != "num" {
+		t.Errorf("expected names from tags, got %q and %q", p.EnvName(), p.FlagName())
+	}
+
+	join := func(path []string) string {
+		return strings.Join(path, ".")
+	}
+	m, err = Scan(&v, Names(join, nil), TagKeys("-", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, _ := m.Module("Inner")
+	if p, ok := inner.Parameter("S"); !ok || p.EnvName() != "Inner.S" {
+		t.Errorf("expected custom name mapping")
+	}
+	if p, _ := m.Parameter("N"); p.EnvName() != "N" || p.FlagName() != "num" {
+		t.Errorf("expected custom tag keys, got %q and %q", p.EnvName(), p.FlagName())
+	}
+
+	hook := func(ptr interface{}) (value.Value, bool) {
+		if _, ok := ptr.(*chan byte); ok {
+			s := ""
+			return value.ValueOf(&s)
+		}
+		return nil, false
+	}
+	if _, err := ScanWarn(&v, ValueHook(hook), EmbedUnexported()); err != nil {
+		t.Errorf("expected all fields to be converted, got %s", err)
+	}
+}
+
+// checkParameter checks that loading bad into the parameter at path
+// fails without modifying it and that the representation of good round-trips.
+func checkParameter(t *testing.T, m Module, path, bad, good string) {
+	t.Helper()
+	p, ok := m.Parameter(path)
+	if !ok {
+		t.Errorf("%s: expected a parameter", path)
+		return
+	}
+	before := p.String()
+	if _, err := Load(m, Map("test", map[string]string{path: bad})); err == nil {
+		t.Errorf("%s: expected an error loading %q", path, bad)
+	}
+	if got := p.String(); got != before {
+		t.Errorf("%s: failed parse of %q changed %q to %q", path, bad, before, got)
+	}
+	if _, err := Load(m, Map("test", map[string]string{path: good})); err != nil {
+		t.Errorf("%s: loading %q failed: %s", path, good, err)
+		return
+	}
+	str := p.String()
+	if _, err := Load(m, Map("test", map[string]string{path: str})); err != nil || p.String() != str {
+		t.Errorf("%s: %q does not round-trip, got %q: %v", path, str, p.String(), err)
+	}
+}
+
+func TestScanSliceParameter(t *testing.T) {
+	v := struct {
+		Empty []string
+		Ports []int    `sep:";"`
+		Raw   []string `quote:"false"`
+		Users []struct {
+			Name string
+		}
+	}{Empty: []string{""}, Ports: []int{80}}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := m.Parameter("Empty"); p.String() != `""` {
+		t.Errorf("single empty element must be quoted, got %q", p)
+	}
+	if users, ok := m.Module("Users"); !ok || len(users.Modules()) != 0 {
+		t.Errorf("expected empty slice of structs as empty module")
+	}
+	checkParameter(t, m, "Empty", `"a`, `"",""`)
+	checkParameter(t, m, "Ports", "80;x", "80;443")
+	// without quoting, all input is valid
+	if _, err := Load(m, Map("test", map[string]string{"Raw": `"x",y`})); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Ports) != 2 || v.Ports[1] != 443 || len(v.Raw) != 2 || v.Raw[0] != `"x"` {
+		t.Errorf("unexpected values %+v", v)
+	}
+}
+
+func TestScanTextUnmarshaler(t *testing.T) {
+	v := struct {
+		Addr  netip.Addr
+		Big   *big.Int
+		Level slog.Level
+	}{Level: slog.LevelWarn}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Plan(m, Map("test", map[string]string{"Big": "1"})); err != nil || v.Big != nil {
+		t.Errorf("Plan must not allocate nil pointers: %v", err)
+	}
+	checkParameter(t, m, "Big", "1x", "1000000000000000000000")
+	if v.Big == nil || v.Big.String() != "1000000000000000000000" {
+		t.Errorf("expected nil pointer to be allocated, got %v", v.Big)
+	}
+	checkParameter(t, m, "Addr", "::g", "::1")
+	checkParameter(t, m, "Level", "loud", "error+2")
+}
+
+func TestScanNamedScalar(t *testing.T) {
+	type Port uint16
+	type Mode string
+	v := struct {
+		Port  Port
+		Mode  Mode
+		Ports []Port
+	}{Port: 80}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkParameter(t, m, "Port", "70000", "8080")
+	checkParameter(t, m, "Ports", "1,-1", "1,2")
+	for path, want := range map[string]interface{}{"Port": Port(0), "Mode": Mode("")} {
+		p, _ := m.Parameter(path)
+		if got := underlying(p).(value.Value).Get(); reflect.TypeOf(got) != reflect.TypeOf(want) {
+			t.Errorf("%s: Get must retrieve the named type, got %T", path, got)
+		}
+	}
+}
+
+func TestScanTime(t *testing.T) {
+	v := struct {
+		Start  time.Time
+		Cutoff time.Time `layout:"2006-01-02" tz:"America/New_York"`
+		Since  time.Time `layout:"unix"`
+	}{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the zero time round-trips
+	checkParameter(t, m, "Start", "2024-13-01T00:00:00Z", "0001-01-01T00:00:00Z")
+	checkParameter(t, m, "Cutoff", "2024-12-31T00:00:00Z", "2024-12-31")
+	checkParameter(t, m, "Since", "1.5.0", "86400")
+	if v.Cutoff.Location().String() != "America/New_York" || v.Since.Unix() != 86400 {
+		t.Errorf("unexpected values %+v", v)
+	}
+}
+
+func TestScanTimeZoneInvalid(t *testing.T) {
+	v := struct {
+		Broken time.Time `tz:"Nowhere/Unknown"`
+	}{}
+	_, err := Scan(&v)
+	if err == nil {
+		t.Fatalf("expected an error on the unknown time zone")
+	}
+	want := "Broken: tz: unknown time zone Nowhere/Unknown"
+	if err.Error() != want {
+		t.Errorf("want error %q, got %q", want, err)
+	}
+}
+
+func TestScanNetwork(t *testing.T) {
+	v := struct {
+		Listen   value.HostPort
+		Upstream *url.URL
+		Network  *net.IPNet
+	}{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := m.Parameter("Upstream"); !ok || p.String() != "" {
+		t.Fatalf("expected nil *url.URL as unset parameter")
+	}
+	_, err = Load(m, Map("test", map[string]string{"Upstream": "http://%zz"}))
+	if err == nil || v.Upstream != nil {
+		t.Errorf("failed parse must leave nil pointer unset: %v", err)
+	}
+	checkParameter(t, m, "Upstream", "http://%zz", "https://example.com/api?q=1")
+	if v.Upstream == nil || v.Upstream.Host != "example.com" {
+		t.Errorf("unexpected URL %v", v.Upstream)
+	}
+	checkParameter(t, m, "Network", "192.0.2.0/33", "192.0.2.0/24")
+	checkParameter(t, m, "Listen", "8080", "[::1]:8080")
+	_, err = Plan(m, Map("test", map[string]string{"Listen": "8080"}))
+	if err == nil || !strings.Contains(err.Error(), "host:port") {
+		t.Errorf("expected error explaining the expected input, got %v", err)
+	}
+}
+
+func TestScanRegistry(t *testing.T) {
+	type Celsius struct {
+		Degrees float64
+	}
+	v := struct {
+		Temp  Celsius
+		Temps []Celsius
+		Max   value.Optional[Celsius]
+	}{Temps: []Celsius{}}
+	r := value.NewRegistry()
+	r.Register(reflect.TypeOf(Celsius{}), func(ptr interface{}) value.Value {
+		v, _ := value.ValueOf(&ptr.(*Celsius).Degrees)
+		return v
+	})
+	m, err := Scan(&v, Registry(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(m.Parameters()); n != 3 {
+		t.Fatalf("expected 3 parameters, got %d", n)
+	}
+	src := Map("test", map[string]string{"Temp": "21.5", "Temps": "1,2", "Max": "30"})
+	if _, err := Load(m, src); err != nil {
+		t.Fatal(err)
+	}
+	if v.Temp.Degrees != 21.5 || len(v.Temps) != 2 || v.Max.Or(Celsius{}).Degrees != 30 {
+		t.Errorf("unexpected values %+v", v)
+	}
+
+	m, _ = ScanWarn(&v)
+	if n := len(m.Parameters()); n != 1 {
+		t.Errorf("per-scan registries must not affect other scans, got %d parameters", n)
+	}
+}
+
+func TestScanOptional(t *testing.T) {
+	v := struct {
+		Timeout *time.Duration
+		Retries value.Optional[int]
+		Debug   *bool
+		Since   *time.Time `layout:"2006-01-02"`
+	}{}
+	m, err := ScanWarn(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(m.Parameters()); n != 4 {
+		t.Fatalf("expected 4 parameters, got %d", n)
+	}
+	for _, p := range m.Parameters() {
+		if u, ok := underlying(p).(value.Unsettable); !ok || u.IsSet() {
+			t.Errorf("%s: expected unset value", p.Path())
+		}
+	}
+	src := Map("test", map[string]string{
+		"Timeout": "0s",
+		"Retries": "0",
+		"Since":   "2024-05-01",
+	})
+	if _, err := Plan(m, src); err != nil {
+		t.Fatal(err)
+	}
+	if v.Timeout != nil || v.Retries.IsSet() || v.Since != nil {
+		t.Errorf("Plan must not modify values")
+	}
+	if _, err := Load(m, src); err != nil {
+		t.Fatal(err)
+	}
+	if v.Timeout == nil || *v.Timeout != 0 || !v.Retries.IsSet() || v.Debug != nil || v.Since.Day() != 1 {
+		t.Errorf("unexpected values %+v", v)
+	}
+	if !isBool(m.Parameters()[2]) {
+		t.Errorf("expected bool flag for *bool")
+	}
+}
@@ -0,0 +1,284 @@
+package envflag
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/confactor/envflag/value"
+)
+
+// Schema generates a JSON Schema (draft 2020-12) describing a module.
+//
+// Modules are described as objects with their fields or map keys as
+// properties, modules containing array or slice elements as arrays.
+// The items of arrays are described by a zero element.
+// The type of a parameter is inferred from the value retrieved by Get.
+// Values of named types implementing fmt.Stringer, e.g. time.Duration,
+// and of types implementing encoding.TextMarshaler are strings.
+//
+// Values implementing value.Enumeration are strings restricted to their names.
+// Parameters can be constrained with the tags `enum:"a,b,c"`, `min:"0"`,
+// `max:"10"` and `required:"true"`. The `usage` tag provides a description.
+// Defaults of parameters tagged with `secret:"true"` are omitted.
+type Schema struct {
+	// ID is the URI of the schema.
+	ID string
+
+	// Title of the schema.
+	Title string
+}
+
+// schemaDraft is the URI of the JSON Schema meta schema.
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Write writes the schema for m to w.
+func (s *Schema) Write(w io.Writer, m Module) error {
+	schema := moduleSchema(m)
+	schema["$schema"] = schemaDraft
+	if s.ID != "" {
+		schema["$id"] = s.ID
+	}
+	if s.Title != "" {
+		schema["title"] = s.Title
+	}
+	buf, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(buf, '\n'))
+	return err
+}
+
+type jsonObject map[string]interface{}
+
+// moduleSchema describes m as an object or an array.
+func moduleSchema(m Module) jsonObject {
+	if mod, ok := m.(*module); ok && mod.list {
+		schema := jsonObject{"type": "array"}
+		// elements of arrays and slices share a type
+		if mod.item != nil {
+			m = mod.item
+		}
+		if ms := m.Modules(); len(ms) > 0 {
+			schema["items"] = moduleSchema(ms[0])
+		} else if ps := m.Parameters(); len(ps) > 0 {
+			schema["items"] = parameterSchema(ps[0])
+		}
+		return schema
+	}
+	props := jsonObject{}
+	var required []string
+	for _, sub := range m.Modules() {
+		props[unescape(sub.Name())] = moduleSchema(sub)
+	}
+	for _, p := range m.Parameters() {
+		name := unescape(p.Name())
+		props[name] = parameterSchema(p)
+		if tagged(p, "required") {
+			required = append(required, name)
+		}
+	}
+	schema := jsonObject{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// unescape retrieves the map key or field name of the path segment name.
+func unescape(name string) string {
+	if !strings.Contains(name, `\`) {
+		return name
+	}
+	key := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+1 < len(name) {
+			i++
+		}
+		key = append(key, name[i])
+	}
+	return string(key)
+}
+
+// parameterSchema describes the value of p.
+func parameterSchema(p Parameter) jsonObject {
+	schema := typeSchema(registryOf(p), valueType(p))
+	if _, ok := underlying(p).(value.Enumeration); ok {
+		// values are represented by their names
+		schema = jsonObject{"type": "string"}
+	}
+	if desc := strings.Join(strings.Fields(p.Tag("usage")), " "); desc != "" {
+		schema["description"] = desc
+	}
+	typ, _ := schema["type"].(string)
+	if def := p.Default(); tagged(p, "secret") {
+		schema["writeOnly"] = true
+	} else if v, ok := jsonValue(typ, def); ok {
+		schema["default"] = v
+	} else if v, ok := jsonCollection(p); ok {
+		schema["default"] = v
+	}
+	if names := enum(p); len(names) > 0 {
+		var vals []interface{}
+		for _, name := range names {
+			if v, ok := jsonValue(typ, name); ok {
+				vals = append(vals, v)
+			}
+		}
+		schema["enum"] = vals
+	}
+	for tag, keys := range map[string][3]string{
+		"min": {"minimum", "minLength", "minItems"},
+		"max": {"maximum", "maxLength", "maxItems"},
+	} {
+		limit, ok := jsonValue("number", p.Tag(tag))
+		if !ok {
+			continue
+		}
+		switch typ {
+		case "integer", "number":
+			schema[keys[0]] = limit
+		case "string":
+			schema[keys[1]] = limit
+		case "array":
+			schema[keys[2]] = limit
+		}
+	}
+	return schema
+}
+
+var (
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// registryOf retrieves the registry creating the values of p.
+func registryOf(p Parameter) *value.Registry {
+	if param, ok := p.(*parameter); ok && param.cfg != nil {
+		return param.cfg.registry
+	}
+	return value.NewRegistry()
+}
+
+// typeSchema describes values of type t created by r.
+// Types only supported by r, e.g. structs, are represented as strings.
+func typeSchema(r *value.Registry, t reflect.Type) jsonObject {
+	if t == nil {
+		return jsonObject{}
+	}
+	if t.PkgPath() != "" && (t.Implements(stringerType) || reflect.PointerTo(t).Implements(stringerType)) {
+		return jsonObject{"type": "string"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return jsonObject{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return jsonObject{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return jsonObject{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return jsonObject{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return jsonObject{"type": "number"}
+	case reflect.String:
+		return jsonObject{"type": "string"}
+	case reflect.Array, reflect.Slice:
+		return jsonObject{"type": "array", "items": typeSchema(r, t.Elem())}
+	case reflect.Map:
+		return jsonObject{"type": "object", "additionalProperties": typeSchema(r, t.Elem())}
+	}
+	if _, ok := r.ValueOf(reflect.New(t).Interface()); ok {
+		return jsonObject{"type": "string"}
+	}
+	return jsonObject{}
+}
+
+// jsonCollection converts the current value of a list or map parameter p
+// to a JSON array or object.
+func jsonCollection(p Parameter) (json.RawMessage, bool) {
+	t := valueType(p)
+	if t == nil || t.Kind() != reflect.Slice && t.Kind() != reflect.Map {
+		return nil, false
+	}
+	r := registryOf(p)
+	rv := reflect.ValueOf(p.(interface{ Get() interface{} }).Get())
+	var v interface{}
+	if t.Kind() == reflect.Slice {
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			elem, ok := jsonElement(r, rv.Index(i))
+			if !ok {
+				return nil, false
+			}
+			list[i] = elem
+		}
+		v = list
+	} else {
+		obj := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, ok := valueString(r, iter.Key())
+			if !ok {
+				return nil, false
+			}
+			elem, ok := jsonElement(r, iter.Value())
+			if !ok {
+				return nil, false
+			}
+			obj[key] = elem
+		}
+		v = obj
+	}
+	b, err := json.Marshal(v)
+	return b, err == nil
+}
+
+// valueString retrieves the representation of v created by r.
+func valueString(r *value.Registry, v reflect.Value) (string, bool) {
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	val, ok := r.ValueOf(ptr.Interface())
+	if !ok {
+		return "", false
+	}
+	return val.String(), true
+}
+
+// jsonElement converts an element of a list or map to a JSON value.
+func jsonElement(r *value.Registry, v reflect.Value) (interface{}, bool) {
+	str, ok := valueString(r, v)
+	if !ok {
+		return nil, false
+	}
+	typ, _ := typeSchema(r, v.Type())["type"].(string)
+	return jsonValue(typ, str)
+}
+
+// jsonValue converts the representation str of a value to a JSON value of type typ.
+func jsonValue(typ, str string) (interface{}, bool) {
+	switch typ {
+	case "string":
+		return str, true
+	case "boolean", "integer", "number":
+		if str == "" || !json.Valid([]byte(str)) {
+			return nil, false
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(str), &v); err != nil {
+			return nil, false
+		}
+		if _, isBool := v.(bool); isBool != (typ == "boolean") {
+			return nil, false
+		}
+		return json.RawMessage(str), true
+	}
+	return nil, false
+}
@@ -0,0 +1,216 @@
+package envflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/confactor/envflag/value"
+)
+
+func TestSchema(t *testing.T) {
+	type Upstream struct {
+		URL string `required:"true"`
+	}
+	v := struct {
+		Mode      string        `enum:"fast,safe" usage:"Processing mode."`
+		Workers   uint8         `min:"1" max:"16"`
+		Timeout   time.Duration `secret:"true"`
+		Ratio     float64
+		Upstreams []Upstream
+	}{
+		Mode:      "safe",
+		Workers:   4,
+		Upstreams: make([]Upstream, 1),
+	}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	s := &Schema{ID: "https://example.com/config.json", Title: "Config"}
+	if err := s.Write(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$id": "https://example.com/config.json",
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "Mode": {
+      "default": "safe",
+      "description": "Processing mode.",
+      "enum": [
+        "fast",
+        "safe"
+      ],
+      "type": "string"
+    },
+    "Ratio": {
+      "default": 0,
+      "type": "number"
+    },
+    "Timeout": {
+      "type": "string",
+      "writeOnly": true
+    },
+    "Upstreams": {
+      "items": {
+        "properties": {
+          "URL": {
+            "default": "",
+            "type": "string"
+          }
+        },
+        "required": [
+          "URL"
+        ],
+        "type": "object"
+      },
+      "type": "array"
+    },
+    "Workers": {
+      "default": 4,
+      "maximum": 16,
+      "minimum": 1,
+      "type": "integer"
+    }
+  },
+  "title": "Config",
+  "type": "object"
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("want schema\n%s\ngot\n%s", want, got)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("invalid JSON")
+	}
+}
+
+func TestSchemaEnumeration(t *testing.T) {
+	type Level uint8
+	v := struct {
+		Level Level
+	}{}
+	levels := map[string]Level{"low": 0, "high": 1}
+	m, err := Scan(&v, ValueHook(func(ptr interface{}) (value.Value, bool) {
+		if l, ok := ptr.(*Level); ok {
+			return value.EnumOf(l, levels, true), true
+		}
+		return nil, false
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := parameterSchema(m.Parameters()[0])
+	want := `{"default":"low","enum":["low","high"],"type":"string"}`
+	if got, _ := json.Marshal(schema); string(got) != want {
+		t.Errorf("want schema %s, got %s", want, got)
+	}
+	buf := &bytes.Buffer{}
+	if err := (&Usage{}).Write(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "one of low, high") {
+		t.Errorf("expected names in usage, got\n%s", buf)
+	}
+	if names := choices(m.Parameters()[0]); len(names) != 2 {
+		t.Errorf("expected names as completion choices, got %q", names)
+	}
+}
+
+func TestSchemaCollections(t *testing.T) {
+	type Node struct {
+		Name     string
+		Children []Node
+	}
+	type Upstream struct {
+		URL string
+	}
+	v := struct {
+		Upstreams []Upstream
+		Routes    map[string]Upstream
+		Tree      []Node
+	}{
+		Routes: map[string]Upstream{"a/b": {}},
+	}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := (&Schema{}).Write(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties struct {
+			Upstreams struct {
+				Items struct {
+					Properties map[string]interface{}
+				}
+			}
+			Routes struct {
+				Properties map[string]interface{}
+			}
+			Tree struct {
+				Items struct {
+					Properties map[string]interface{}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &schema); err != nil {
+		t.Fatal(err)
+	}
+	props := schema.Properties
+	if _, ok := props.Upstreams.Items.Properties["URL"]; !ok {
+		t.Errorf("expected items of empty slice to be described by the element type:\n%s", buf)
+	}
+	if _, ok := props.Routes.Properties["a/b"]; !ok {
+		t.Errorf("expected map entry named by its key:\n%s", buf)
+	}
+	if _, ok := props.Tree.Items.Properties["Children"]; !ok {
+		t.Errorf("expected items of recursive type:\n%s", buf)
+	}
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	v := struct {
+		Temps []celsius
+	}{Temps: []celsius{{1}, {2}}}
+	r := value.NewRegistry()
+	r.Register(reflect.TypeOf(celsius{}), func(ptr interface{}) value.Value {
+		return celsiusValue{ptr.(*celsius)}
+	})
+	m, err := Scan(&v, Registry(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := (&Schema{}).Write(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	want := `"Temps": {
+      "default": [
+        "1",
+        "2"
+      ],
+      "items": {
+        "type": "string"
+      },
+      "type": "array"
+    }`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("want schema containing\n%s\ngot\n%s", want, buf)
+	}
+	buf.Reset()
+	if err := (&Example{}).JSON(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	if want := `"Temps": ["1","2"]`; !strings.Contains(buf.String(), want) {
+		t.Errorf("want example containing\n%s\ngot\n%s", want, buf)
+	}
+}
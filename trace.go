@@ -1,5 +1,7 @@
 package envflag
 
+import "strings"
+
 // scantracer provides error tracing functionality.
 type scantracer struct {
 	path
@@ -14,9 +16,13 @@ type scantracer struct {
 	duplicates int
 }
 
-// warning retrieves problems occuring during the scan in an accessible format.
-func (s *scantracer) warning() *ScanWarnings {
-	if s == nil || s.duplicates == 0 && len(s.skipped) == 0 {
+// warning retrieves problems occuring during the scan of m in an accessible format.
+func (s *scantracer) warning(m *module) *ScanWarnings {
+	if s == nil {
+		return nil
+	}
+	cols := collisions(m)
+	if s.duplicates == 0 && len(s.skipped) == 0 && len(cols) == 0 {
 		return nil
 	}
 	var dups [][]string
@@ -31,7 +37,49 @@ func (s *scantracer) warning() *ScanWarnings {
 	return &ScanWarnings{
 		Duplicates: dups,
 		Skipped:    s.skipped,
+		Collisions: cols,
+	}
+}
+
+// collisions retrieves groups of paths to parameters sharing
+// an environment variable or a flag name, including deprecated names.
+func collisions(m *module) [][]string {
+	var (
+		names  []string
+		paths  = make(map[string][]string)
+		groups [][]string
+		known  = make(map[string]struct{})
+	)
+	add := func(name, path string) {
+		if _, found := paths[name]; !found {
+			names = append(names, name)
+		}
+		paths[name] = append(paths[name], path)
+	}
+	eachParameter(m, func(p Parameter) {
+		add("env:"+p.EnvName(), p.Path())
+		add("flag:"+p.FlagName(), p.Path())
+		for _, alias := range p.EnvAliases() {
+			add("env:"+alias, p.Path())
+		}
+		for _, alias := range p.FlagAliases() {
+			add("flag:"+alias, p.Path())
+		}
+	})
+	for _, name := range names {
+		group := paths[name]
+		if len(group) < 2 {
+			continue
+		}
+		// report groups colliding on both names once
+		key := strings.Join(group, "\x00")
+		if _, found := known[key]; found {
+			continue
+		}
+		known[key] = struct{}{}
+		groups = append(groups, group)
 	}
+	return groups
 }
 
 func (s *scantracer) register(ptr interface{}) bool {
@@ -51,8 +99,9 @@ func (s *scantracer) skip() {
 // It holds paths to problematic fields, where a path is the sequence of
 // struct field names starting at the root that have to be traversed to reach a field.
 //
-// A path starts at the root element and contains field names or slice indices separated
-// by a slash ('/').
+// A path starts at the root element and contains field names, slice indices or map keys
+// separated by a slash ('/'). Slashes and backslashes in map keys are escaped with
+// a backslash.
 type ScanWarnings struct {
 
 	// Duplicates contains slices with paths to fields holding pointers
@@ -65,6 +114,10 @@ type ScanWarnings struct {
 	// a parameter, it is unexported or nil or if it can neither be
 	// converted by ValueOf nor scanned as an inner struct.
 	Skipped []string
+
+	// Collisions contains slices with paths to parameters sharing
+	// an environment variable or a flag name, including deprecated names.
+	Collisions [][]string
 }
 
 func (w *ScanWarnings) Error() string {
@@ -86,6 +139,19 @@ func (w *ScanWarnings) Error() string {
 		msg = append(msg, "skipped "...)
 		msg = appendgroup(msg, w.Skipped)
 	}
+	if len(w.Collisions) > 0 {
+		if len(msg) > 0 {
+			msg = append(msg, " and "...)
+		}
+		msg = append(msg, "found name collisions ("...)
+		for i, group := range w.Collisions {
+			if i > 0 {
+				msg = append(msg, "), ("...)
+			}
+			msg = appendgroup(msg, group)
+		}
+		msg = append(msg, ')')
+	}
 	return string(msg)
 }
 
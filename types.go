@@ -9,6 +9,10 @@ import (
 type Field interface {
 	Name() string
 
+	// Path retrieves the slash-delimited field names leading
+	// from the root module to the field.
+	Path() string
+
 	// Tag retrieves a tag.
 	// The key "" retrieves all tags.
 	Tag(key string) string
@@ -23,6 +27,27 @@ type Value interface {
 type Parameter interface {
 	Field
 	Value
+
+	// EnvName retrieves the name of the environment variable
+	// for the parameter, excluding any prefix.
+	EnvName() string
+
+	// FlagName retrieves the name of the command line flag
+	// for the parameter.
+	FlagName() string
+
+	// Aliases retrieves all deprecated names of the parameter.
+	Aliases() []string
+
+	// EnvAliases retrieves the deprecated environment variable names
+	// of the parameter, excluding any prefix.
+	EnvAliases() []string
+
+	// FlagAliases retrieves the deprecated flag names of the parameter.
+	FlagAliases() []string
+
+	// Default retrieves the representation of the value at the time it was scanned.
+	Default() string
 }
 
 // Module is a collection of modules and parameters.
@@ -40,14 +65,30 @@ type boolValue interface {
 }
 
 type field struct {
-	name string
-	tag  reflect.StructTag
+	name     string
+	path     string
+	names    []string
+	tag      reflect.StructTag
+	embedded bool
+	exported bool
 }
 
 // parameter is a configurable value.
 type parameter struct {
 	field
 	value.Value
+	env     string
+	flag    string
+	aliases []string
+	// envAliases and flagAliases partition aliases.
+	envAliases, flagAliases []string
+	def                     string
+	// cfg is the configuration of the scan.
+	cfg *config
+	// ptr references the memory of the value, valueOf creates
+	// values of copies of it like the value.
+	ptr     interface{}
+	valueOf func(ptr interface{}) (value.Value, bool)
 }
 
 // module is a collection of configurable values and other modules.
@@ -55,6 +96,11 @@ type module struct {
 	field
 	module []*module
 	param  []*parameter
+
+	// list reports whether the module contains the elements of an array or a slice.
+	list bool
+	// item contains a zero element of a list, if it can be scanned.
+	item *module
 }
 
 type path struct {
@@ -85,6 +131,10 @@ func (f *field) Name() string {
 	return f.name
 }
 
+func (f *field) Path() string {
+	return f.path
+}
+
 func (f *field) Tag(key string) string {
 	if key == "" {
 		return string(f.tag)
@@ -92,6 +142,30 @@ func (f *field) Tag(key string) string {
 	return f.tag.Get(key)
 }
 
+func (p *parameter) EnvName() string {
+	return p.env
+}
+
+func (p *parameter) FlagName() string {
+	return p.flag
+}
+
+func (p *parameter) Aliases() []string {
+	return p.aliases
+}
+
+func (p *parameter) EnvAliases() []string {
+	return p.envAliases
+}
+
+func (p *parameter) FlagAliases() []string {
+	return p.flagAliases
+}
+
+func (p *parameter) Default() string {
+	return p.def
+}
+
 func (m *module) Module(name string) (Module, bool) {
 	for _, m := range m.module {
 		if m.name == name {
@@ -0,0 +1,181 @@
+package envflag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Usage formats help text for the parameters in a module.
+//
+// For each parameter, it lists the flag and environment variable names,
+// the type, the allowed, default and current values and the text of the `usage` tag.
+// Parameters tagged with `required:"true"` or `secret:"true"` are marked,
+// values of secret parameters are hidden.
+// Parameters are grouped by module.
+type Usage struct {
+	// EnvPrefix is prepended to environment variable names.
+	EnvPrefix string
+
+	// FlagPrefix is prepended to flag names; "-" if empty.
+	FlagPrefix string
+
+	// Width is the maximum width of a line; 80 if not positive.
+	Width int
+}
+
+// hidden replaces values of secret parameters.
+const hidden = "******"
+
+// Write writes the help text for m to w.
+func (u *Usage) Write(w io.Writer, m Module) error {
+	width, dash := u.Width, u.FlagPrefix
+	if width <= 0 {
+		width = 80
+	}
+	if dash == "" {
+		dash = "-"
+	}
+	var buf []byte
+	eachModule(m, func(m Module) {
+		params := m.Parameters()
+		if len(params) == 0 {
+			return
+		}
+		indent := ""
+		if path := m.Path(); path != "" {
+			if len(buf) > 0 {
+				buf = append(buf, '\n')
+			}
+			buf = append(buf, path...)
+			buf = append(buf, ":\n"...)
+			indent = "  "
+		}
+		for _, p := range params {
+			buf = append(buf, indent...)
+			buf = append(buf, "  "...)
+			buf = append(buf, dash...)
+			buf = append(buf, p.FlagName()...)
+			if t := typeName(p); t != "" {
+				buf = append(buf, ' ')
+				buf = append(buf, t...)
+			}
+			buf = append(buf, ", $"...)
+			buf = append(buf, u.EnvPrefix...)
+			buf = append(buf, p.EnvName()...)
+			if tagged(p, "required") {
+				buf = append(buf, " (required)"...)
+			}
+			if tagged(p, "secret") {
+				buf = append(buf, " (secret)"...)
+			}
+			buf = append(buf, '\n')
+			text := indent + "        "
+			buf = appendWrapped(buf, p.Tag("usage"), text, width)
+			buf = appendWrapped(buf, values(p), text, width)
+		}
+	})
+	_, err := w.Write(buf)
+	return err
+}
+
+// Func retrieves a function writing the help text for m
+// to the output of fs. It is intended to be used as fs.Usage.
+// Errors writing the help text are logged with the standard logger.
+func (u *Usage) Func(fs *flag.FlagSet, m Module) func() {
+	return func() {
+		out := fs.Output()
+		if name := fs.Name(); name != "" {
+			fmt.Fprintf(out, "Usage of %s:\n", name)
+		} else {
+			fmt.Fprint(out, "Usage:\n")
+		}
+		if err := u.Write(out, m); err != nil {
+			log.Printf("envflag: writing usage failed: %s", err)
+		}
+	}
+}
+
+// eachModule calls fn for m and all its submodules.
+func eachModule(m Module, fn func(m Module)) {
+	fn(m)
+	for _, sub := range m.Modules() {
+		eachModule(sub, fn)
+	}
+}
+
+// valueType retrieves the type of the value of p or nil if it is unknown.
+func valueType(p Parameter) reflect.Type {
+	g, ok := p.(interface {
+		Get() interface{}
+	})
+	if !ok {
+		return nil
+	}
+	return reflect.TypeOf(g.Get())
+}
+
+// typeName retrieves the name of the type of the value of p.
+func typeName(p Parameter) string {
+	if t := valueType(p); t != nil {
+		return t.String()
+	}
+	return ""
+}
+
+// values describes the allowed, default and current values of p.
+func values(p Parameter) string {
+	def, cur := p.Default(), p.String()
+	changed := cur != def
+	if tagged(p, "secret") {
+		if def != "" {
+			def = hidden
+		}
+		if cur != "" {
+			cur = hidden
+		}
+	}
+	var desc []string
+	if names := enum(p); len(names) > 0 {
+		desc = append(desc, "one of "+strings.Join(names, ", "))
+	}
+	if def != "" {
+		desc = append(desc, "default "+strconv.Quote(def))
+	}
+	if changed {
+		desc = append(desc, "current "+strconv.Quote(cur))
+	}
+	if len(desc) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(desc, ", ") + ")"
+}
+
+// appendWrapped appends text to buf. Words are wrapped to lines of at most width bytes
+// starting with indent. Words longer than a line are not split.
+func appendWrapped(buf []byte, text, indent string, width int) []byte {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return buf
+	}
+	n := 0
+	for i, word := range words {
+		if i > 0 && n+1+len(word) <= width {
+			buf = append(buf, ' ')
+			n++
+		} else {
+			if i > 0 {
+				buf = append(buf, '\n')
+			}
+			buf = append(buf, indent...)
+			n = len(indent)
+		}
+		buf = append(buf, word...)
+		n += len(word)
+	}
+	return append(buf, '\n')
+}
@@ -0,0 +1,100 @@
+package envflag
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsage(t *testing.T) {
+	v := struct {
+		Verbose bool `usage:"Log every request and response in detail."`
+		DB      struct {
+			Host     string        `required:"true" usage:"Host name of the database."`
+			Password string        `secret:"true"`
+			Timeout  time.Duration `usage:"Timeout for queries."`
+		}
+	}{}
+	v.DB.Timeout = time.Second
+	v.DB.Password = "default"
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.DB.Password = "changed"
+	v.DB.Timeout = time.Minute
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	out := &bytes.Buffer{}
+	fs.SetOutput(out)
+	u := &Usage{EnvPrefix: "APP_", Width: 40}
+	fs.Usage = u.Func(fs, m)
+	fs.Usage()
+
+	want := strings.Join([]string{
+		"Usage of test:",
+		"  -verbose bool, $APP_VERBOSE",
+		"        Log every request and response",
+		"        in detail.",
+		`        (default "false")`,
+		"",
+		"DB:",
+		"    -db-host string, $APP_DB_HOST (required)",
+		"          Host name of the database.",
+		"    -db-password string, $APP_DB_PASSWORD (secret)",
+		`          (default "******", current`,
+		`          "******")`,
+		"    -db-timeout time.Duration, $APP_DB_TIMEOUT",
+		"          Timeout for queries.",
+		`          (default "1s", current "1m0s")`,
+		"",
+	}, "\n")
+	if got := out.String(); got != want {
+		t.Errorf("want usage\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestUsageSecretUnchanged(t *testing.T) {
+	v := struct {
+		Password string `secret:"true"`
+	}{Password: "default"}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := (&Usage{}).Write(out, m); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); strings.Contains(got, "current") {
+		t.Errorf("unchanged secret must not be reported as current value:\n%s", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken")
+}
+
+func TestUsageFuncError(t *testing.T) {
+	v := struct{ Verbose bool }{}
+	m, err := Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(failingWriter{})
+	logged := &bytes.Buffer{}
+	log.SetOutput(logged)
+	defer log.SetOutput(os.Stderr)
+	(&Usage{}).Func(fs, m)()
+	if got := logged.String(); !strings.Contains(got, "writing usage failed: broken") {
+		t.Errorf("expected write error to be logged, got %q", got)
+	}
+}
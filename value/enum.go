@@ -0,0 +1,94 @@
+package value
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Enumeration is implemented by values restricted to a set of names.
+type Enumeration interface {
+	Value
+
+	// Names retrieves the allowed names.
+	Names() []string
+}
+
+// Symbol is the type of values of enumerations.
+type Symbol interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~string
+}
+
+// EnumOf retrieves a value of the enumeration referenced by ptr.
+// It is set by the names in the keys of names to the mapped values.
+// If fold is true, names are case-insensitive.
+//
+// If several names map to the same value, the first name in lexical order
+// is the canonical name used by String and AppendTo. Values without a name
+// are formatted as numbers or strings. Names retrieves all names sorted
+// by their values.
+func EnumOf[T Symbol](ptr *T, names map[string]T, fold bool) Enumeration {
+	e := &enumValue[T]{ptr: ptr, values: names, fold: fold}
+	for name := range names {
+		e.names = append(e.names, name)
+	}
+	sort.Slice(e.names, func(i, j int) bool {
+		a, b := names[e.names[i]], names[e.names[j]]
+		if a != b {
+			return a < b
+		}
+		return e.names[i] < e.names[j]
+	})
+	return e
+}
+
+type enumValue[T Symbol] struct {
+	ptr    *T
+	values map[string]T
+	// names contains all names, sorted by value and name.
+	names []string
+	fold  bool
+}
+
+func (p *enumValue[T]) Names() []string  { return append([]string(nil), p.names...) }
+func (p *enumValue[T]) Get() interface{} { return *p.ptr }
+func (p *enumValue[T]) String() string   { return string(p.AppendTo(nil)) }
+
+func (p *enumValue[T]) AppendTo(dest []byte) []byte {
+	for _, name := range p.names {
+		if p.values[name] == *p.ptr {
+			return append(dest, name...)
+		}
+	}
+	return fmt.Append(dest, *p.ptr)
+}
+
+func (p *enumValue[T]) Set(s string) error {
+	if v, ok := p.values[s]; ok {
+		*p.ptr = v
+		return nil
+	}
+	if p.fold {
+		for _, name := range p.names {
+			if strings.EqualFold(name, s) {
+				*p.ptr = p.values[name]
+				return nil
+			}
+		}
+	}
+	quoted := make([]string, len(p.names))
+	for i, name := range p.names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return expected("one of "+strings.Join(quoted, ", "), s, nil)
+}
+
+func (p *enumValue[T]) Copy() Value {
+	c := *p
+	c.ptr = new(T)
+	*c.ptr = *p.ptr
+	return &c
+}
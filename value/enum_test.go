@@ -0,0 +1,54 @@
+package value
+
+import (
+	"strings"
+	"testing"
+)
+
+type level int
+
+const (
+	debug level = iota - 1
+	info
+	warn
+)
+
+func TestEnumOf(t *testing.T) {
+	l := info
+	v := EnumOf(&l, map[string]level{
+		"debug":   debug,
+		"info":    info,
+		"warn":    warn,
+		"warning": warn,
+	}, true)
+	if names := strings.Join(v.Names(), ","); names != "debug,info,warn,warning" {
+		t.Errorf("unexpected names %s", names)
+	}
+	if s := v.String(); s != "info" {
+		t.Errorf("expected info, got %s", s)
+	}
+	if err := v.Set("WARNING"); err != nil || l != warn || v.Get() != warn {
+		t.Errorf("expected warn, got %v: %v", l, err)
+	}
+	if s := v.String(); s != "warn" {
+		t.Errorf("expected canonical name warn, got %s", s)
+	}
+	err := v.Set("error")
+	if err == nil || !strings.Contains(err.Error(), `"debug", "info", "warn", "warning"`) {
+		t.Errorf("expected error listing the names, got %v", err)
+	}
+	l = 7
+	if s := v.String(); s != "7" {
+		t.Errorf("expected unnamed value 7, got %s", s)
+	}
+
+	mode := "fast"
+	v = EnumOf(&mode, map[string]string{"fast": "fast", "safe": "safe"}, false)
+	if err := v.Set("SAFE"); err == nil {
+		t.Errorf("names must be case-sensitive without folding")
+	}
+	c := v.(Copier).Copy()
+	if err := c.Set("safe"); err != nil || mode != "fast" {
+		t.Errorf("copy must be independent: %v", err)
+	}
+}
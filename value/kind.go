@@ -0,0 +1,91 @@
+package value
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// KindOf retrieves a value referenced by ptr based on the kind of the
+// referenced type. It supports named types like `type Port uint16`
+// with bool, string, int, uint or float kinds.
+//
+// Get retrieves a value of the named type. Values are parsed and formatted
+// as those of the underlying builtin type.
+func KindOf(ptr interface{}) (Value, bool) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, false
+	}
+	switch rv.Elem().Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return &kindValue{ptr: rv}, true
+	}
+	return nil, false
+}
+
+type kindValue struct {
+	// ptr is the pointer to the value.
+	ptr reflect.Value
+}
+
+func (p *kindValue) IsBoolFlag() bool { return p.ptr.Elem().Kind() == reflect.Bool }
+func (p *kindValue) Get() interface{} { return p.ptr.Elem().Interface() }
+func (p *kindValue) String() string   { return string(p.AppendTo(nil)) }
+
+func (p *kindValue) AppendTo(dest []byte) []byte {
+	v := p.ptr.Elem()
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(dest, v.Bool())
+	case reflect.String:
+		return append(dest, v.String()...)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(dest, v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(dest, v.Uint(), 10)
+	default:
+		return strconv.AppendFloat(dest, v.Float(), 'g', -1, v.Type().Bits())
+	}
+}
+
+func (p *kindValue) Set(s string) error {
+	v := p.ptr.Elem()
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err == nil {
+			v.SetBool(b)
+		}
+		return err
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err == nil {
+			v.SetInt(i)
+		}
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err == nil {
+			v.SetUint(u)
+		}
+		return err
+	default:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err == nil {
+			v.SetFloat(f)
+		}
+		return err
+	}
+}
+
+func (p *kindValue) Copy() Value {
+	c := reflect.New(p.ptr.Type().Elem())
+	c.Elem().Set(p.ptr.Elem())
+	return &kindValue{ptr: c}
+}
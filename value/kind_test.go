@@ -0,0 +1,46 @@
+package value
+
+import (
+	"flag"
+	"testing"
+)
+
+type (
+	port    uint16
+	mode    string
+	enabled bool
+	ratio   float32
+	offset  int8
+)
+
+func TestValueOfKind(t *testing.T) {
+	p, m, r, o := port(0), mode(""), ratio(0), offset(0)
+	testValidCases(t, &p, port(8080), port(65535))
+	testValidCases(t, &m, mode("fast"), mode(""))
+	testValidCases(t, &r, ratio(0.5), ratio(-1e10))
+	testValidCases(t, &o, offset(-128), offset(127))
+
+	v, _ := ValueOf(&p)
+	if err := v.Set("65536"); err == nil {
+		t.Errorf("Set must fail on overflow")
+	}
+	if err := v.Set("0x10"); err != nil || p != 16 {
+		t.Errorf("Set must accept base prefixes, got %d: %v", p, err)
+	}
+
+	e := enabled(false)
+	v = testValidCases(t, &e, enabled(true), enabled(false))
+	if b, ok := v.(interface{ IsBoolFlag() bool }); !ok || !b.IsBoolFlag() {
+		t.Errorf("named bool types must be bool flags")
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(v.(flag.Value), "e", "")
+	if err := fs.Parse([]string{"-e"}); err != nil || !bool(e) {
+		t.Errorf("expected bool flag to be set: %v", err)
+	}
+
+	c := v.(Copier).Copy()
+	if err := c.Set("false"); err != nil || !bool(e) {
+		t.Errorf("copy must be independent: %v", err)
+	}
+}
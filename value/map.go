@@ -0,0 +1,219 @@
+package value
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MapOf retrieves a value of the map referenced by ptr.
+// Keys and values of the map must be supported by ValueOf
+// and must not be slices or maps themselves.
+//
+// The representation is a list of entries "key=value" separated by sep.
+// A backslash escapes the following character, e.g. `a\=b=c\,d` is
+// the entry with the key "a=b" and the value "c,d".
+// Entries are sorted by key. If a key is given multiple times,
+// the last value is used.
+//
+// Set replaces the map, the previous map is not modified.
+func MapOf(ptr interface{}, sep rune) (Value, bool) {
+	return global.MapOf(ptr, sep)
+}
+
+// MapOf is like the function MapOf, keys and values are supported
+// if they are supported by r.ValueOf.
+func (r *Registry) MapOf(ptr interface{}, sep rune) (Value, bool) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Map {
+		return nil, false
+	}
+	typ := rv.Type().Elem()
+	for _, t := range []reflect.Type{typ.Key(), typ.Elem()} {
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			return nil, false
+		}
+		if _, ok := r.ValueOf(reflect.New(t).Interface()); !ok {
+			return nil, false
+		}
+	}
+	if sep == 0 || sep == '=' || sep == '\\' {
+		return nil, false
+	}
+	return &mapValue{ptr: rv, sep: sep, reg: r}, true
+}
+
+type mapValue struct {
+	// ptr is the pointer to the map.
+	ptr reflect.Value
+	sep rune
+	// reg provides values of keys and elements.
+	reg *Registry
+}
+
+func (p *mapValue) Separator() rune { return p.sep }
+
+func (p *mapValue) Copy() Value {
+	c := *p
+	c.ptr = reflect.New(p.ptr.Type().Elem())
+	c.ptr.Elem().Set(p.ptr.Elem())
+	return &c
+}
+
+func (p *mapValue) Get() interface{} {
+	m := p.ptr.Elem()
+	if m.IsNil() {
+		return m.Interface()
+	}
+	c := reflect.MakeMapWithSize(m.Type(), m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		c.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return c.Interface()
+}
+
+func (p *mapValue) String() string {
+	return string(p.AppendTo(nil))
+}
+
+// format retrieves the representation of v.
+func (p *mapValue) format(v reflect.Value) string {
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	val, _ := p.reg.ValueOf(c.Interface())
+	return val.String()
+}
+
+func (p *mapValue) AppendTo(dest []byte) []byte {
+	m := p.ptr.Elem()
+	keys := m.MapKeys()
+	sortKeys(keys, p.format)
+	for i, key := range keys {
+		if i > 0 {
+			dest = append(dest, string(p.sep)...)
+		}
+		dest = p.appendEscaped(dest, p.format(key), true)
+		dest = append(dest, '=')
+		dest = p.appendEscaped(dest, p.format(m.MapIndex(key)), false)
+	}
+	return dest
+}
+
+// appendEscaped appends s with backslashes and separators escaped,
+// equal signs are only escaped in keys.
+func (p *mapValue) appendEscaped(dest []byte, s string, key bool) []byte {
+	for _, r := range s {
+		if r == '\\' || r == p.sep || key && r == '=' {
+			dest = append(dest, '\\')
+		}
+		dest = append(dest, string(r)...)
+	}
+	return dest
+}
+
+func (p *mapValue) Set(s string) error {
+	typ := p.ptr.Type().Elem()
+	m := reflect.MakeMap(typ)
+	var (
+		entry    strings.Builder
+		key      string
+		keyFound bool
+		escaped  bool
+	)
+	add := func() error {
+		if !keyFound {
+			return errors.New("value: missing '=' in map entry " + entry.String())
+		}
+		k, v := reflect.New(typ.Key()), reflect.New(typ.Elem())
+		kv, _ := p.reg.ValueOf(k.Interface())
+		if err := kv.Set(key); err != nil {
+			return err
+		}
+		vv, _ := p.reg.ValueOf(v.Interface())
+		if err := vv.Set(entry.String()); err != nil {
+			return err
+		}
+		m.SetMapIndex(k.Elem(), v.Elem())
+		entry.Reset()
+		keyFound = false
+		return nil
+	}
+	for _, r := range s {
+		switch {
+		case escaped:
+			entry.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '=' && !keyFound:
+			key, keyFound = entry.String(), true
+			entry.Reset()
+		case r == p.sep:
+			if err := add(); err != nil {
+				return err
+			}
+		default:
+			entry.WriteRune(r)
+		}
+	}
+	if escaped {
+		return errors.New("value: trailing backslash in map")
+	}
+	if s != "" {
+		if err := add(); err != nil {
+			return err
+		}
+	}
+	p.ptr.Elem().Set(m)
+	return nil
+}
+
+// sortKeys sorts map keys of bool, string, integer or floating point kinds
+// by value and other keys by their representation retrieved with format.
+//
+// It matches the sorting of map keys in package walk, which sorts other keys
+// by their default format; both are kept separate to keep the packages
+// independent of each other.
+func sortKeys(keys []reflect.Value, format func(reflect.Value) string) {
+	if len(keys) < 2 {
+		return
+	}
+	var less func(a, b reflect.Value) bool
+	switch keys[0].Kind() {
+	case reflect.Bool:
+		less = func(a, b reflect.Value) bool { return !a.Bool() && b.Bool() }
+	case reflect.String:
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float32, reflect.Float64:
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	default:
+		byString := keysByString{keys: keys, strs: make([]string, len(keys))}
+		for i, key := range keys {
+			byString.strs[i] = format(key)
+		}
+		sort.Sort(byString)
+		return
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return less(keys[i], keys[j])
+	})
+}
+
+// keysByString sorts keys by their representations in strs.
+type keysByString struct {
+	keys []reflect.Value
+	strs []string
+}
+
+func (k keysByString) Len() int           { return len(k.keys) }
+func (k keysByString) Less(i, j int) bool { return k.strs[i] < k.strs[j] }
+func (k keysByString) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.strs[i], k.strs[j] = k.strs[j], k.strs[i]
+}
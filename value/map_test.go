@@ -0,0 +1,88 @@
+package value
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValueOfMap(t *testing.T) {
+	tests := []struct {
+		ptr  interface{}
+		set  string
+		want interface{}
+		str  string
+	}{
+		{new(map[string]string), "", map[string]string{}, ""},
+		{new(map[string]string), "b=2,a=1,b=3", map[string]string{"a": "1", "b": "3"}, "a=1,b=3"},
+		{new(map[string]string), `a\=b=c\,d=e,\\=`, map[string]string{"a=b": "c,d=e", `\`: ""}, `\\=,a\=b=c\,d=e`},
+		{new(map[int]time.Duration), "10=1s,9=1m", map[int]time.Duration{9: time.Minute, 10: time.Second}, "9=1m0s,10=1s"},
+		{new(map[string]bool), "x=true", map[string]bool{"x": true}, "x=true"},
+		// keys of other kinds are sorted by their representation
+		{new(map[netip.Addr]int), "10.0.0.3=3,10.0.0.1=1,10.0.0.2=2", map[netip.Addr]int{
+			netip.MustParseAddr("10.0.0.1"): 1,
+			netip.MustParseAddr("10.0.0.2"): 2,
+			netip.MustParseAddr("10.0.0.3"): 3,
+		}, "10.0.0.1=1,10.0.0.2=2,10.0.0.3=3"},
+	}
+	for _, test := range tests {
+		v, ok := ValueOf(test.ptr)
+		if !ok {
+			t.Fatalf("ValueOf([%T]) failed", test.ptr)
+		}
+		if err := v.Set(test.set); err != nil {
+			t.Errorf("%T Set(%q): %s", test.ptr, test.set, err)
+			continue
+		}
+		if got := v.Get(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%T Get(): expected %v, got %v", test.ptr, test.want, got)
+		}
+		s := v.String()
+		if s != test.str {
+			t.Errorf("%T String(): expected %q, got %q", test.ptr, test.str, s)
+		}
+		// the representation round-trips
+		if err := v.Set(s); err != nil || v.String() != s {
+			t.Errorf("%T: %q does not round-trip", test.ptr, s)
+		}
+	}
+
+	var m map[string]int
+	v, _ := ValueOf(&m)
+	for _, s := range []string{"a", "a=1,b", "a=x", `a=1\`} {
+		if err := v.Set(s); err == nil {
+			t.Errorf("Set(%q) must cause an error", s)
+		}
+	}
+}
+
+func TestMapOf(t *testing.T) {
+	m := map[string]string{"a": "1"}
+	v, ok := MapOf(&m, ';')
+	if !ok {
+		t.Fatal("MapOf failed")
+	}
+	before := m
+	if err := v.Set("x=1,2;y=3"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, map[string]string{"x": "1,2", "y": "3"}) || before["a"] != "1" {
+		t.Errorf("unexpected maps %v, %v", m, before)
+	}
+	c := v.(Copier).Copy()
+	if err := c.Set("z=0"); err != nil {
+		t.Fatal(err)
+	}
+	if len(m) != 2 || c.String() != "z=0" {
+		t.Errorf("copy must be independent, got %v and %q", m, c)
+	}
+	for _, ptr := range []interface{}{m, &map[string][]string{}, &map[string]chan int{}} {
+		if _, ok := MapOf(ptr, ','); ok {
+			t.Errorf("MapOf([%T]) must fail", ptr)
+		}
+	}
+	if _, ok := MapOf(&m, '='); ok {
+		t.Errorf("MapOf with '=' as separator must fail")
+	}
+}
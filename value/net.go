@@ -0,0 +1,176 @@
+package value
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+)
+
+// HostPort is a network address of the form "host:port" as used by net.Dial
+// and net.Listen. The host can be empty, a name, an IPv4 address or an IPv6
+// address in brackets. The port is a number between 0 and 65535.
+type HostPort string
+
+// Host retrieves the host of the address.
+func (hp HostPort) Host() string {
+	host, _, _ := net.SplitHostPort(string(hp))
+	return host
+}
+
+// Port retrieves the port of the address.
+func (hp HostPort) Port() uint16 {
+	_, port, _ := net.SplitHostPort(string(hp))
+	n, _ := strconv.ParseUint(port, 10, 16)
+	return uint16(n)
+}
+
+// expected creates an error explaining which input was expected instead of s.
+// The cause is appended if it is not nil.
+func expected(what, s string, cause error) error {
+	msg := "value: expected " + what + ", got " + strconv.Quote(s)
+	if cause != nil {
+		msg += ": " + cause.Error()
+	}
+	return errors.New(msg)
+}
+
+type ipValue net.IP
+
+func (p *ipValue) Get() interface{} { return append(net.IP(nil), *p...) }
+func (p *ipValue) String() string   { return string(p.AppendTo(nil)) }
+func (p *ipValue) AppendTo(dest []byte) []byte {
+	if len(*p) == 0 {
+		return dest
+	}
+	return append(dest, net.IP(*p).String()...)
+}
+func (p *ipValue) Set(s string) error {
+	if s == "" {
+		*p = nil
+		return nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return expected("IP address like 192.0.2.1 or 2001:db8::1", s, nil)
+	}
+	*p = ipValue(ip)
+	return nil
+}
+
+type ipNetValue net.IPNet
+
+func (p *ipNetValue) Get() interface{} { return net.IPNet(*p) }
+func (p *ipNetValue) String() string   { return string(p.AppendTo(nil)) }
+func (p *ipNetValue) AppendTo(dest []byte) []byte {
+	if len(p.IP) == 0 {
+		return dest
+	}
+	return append(dest, (*net.IPNet)(p).String()...)
+}
+func (p *ipNetValue) Set(s string) error {
+	if s == "" {
+		*p = ipNetValue{}
+		return nil
+	}
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return expected("network in CIDR notation like 192.0.2.0/24 or 2001:db8::/32", s, nil)
+	}
+	*p = ipNetValue(*n)
+	return nil
+}
+
+type addrValue netip.Addr
+
+func (p *addrValue) Get() interface{} { return netip.Addr(*p) }
+func (p *addrValue) String() string   { return string(p.AppendTo(nil)) }
+func (p *addrValue) AppendTo(dest []byte) []byte {
+	text, _ := netip.Addr(*p).MarshalText()
+	return append(dest, text...)
+}
+func (p *addrValue) Set(s string) error {
+	var a netip.Addr
+	if err := a.UnmarshalText([]byte(s)); err != nil {
+		return expected("IP address like 192.0.2.1 or 2001:db8::1", s, nil)
+	}
+	*p = addrValue(a)
+	return nil
+}
+
+type prefixValue netip.Prefix
+
+func (p *prefixValue) Get() interface{} { return netip.Prefix(*p) }
+func (p *prefixValue) String() string   { return string(p.AppendTo(nil)) }
+func (p *prefixValue) AppendTo(dest []byte) []byte {
+	text, _ := netip.Prefix(*p).MarshalText()
+	return append(dest, text...)
+}
+func (p *prefixValue) Set(s string) error {
+	var pfx netip.Prefix
+	if err := pfx.UnmarshalText([]byte(s)); err != nil {
+		return expected("IP prefix in CIDR notation like 192.0.2.0/24 or 2001:db8::/32", s, nil)
+	}
+	*p = prefixValue(pfx)
+	return nil
+}
+
+type addrPortValue netip.AddrPort
+
+func (p *addrPortValue) Get() interface{} { return netip.AddrPort(*p) }
+func (p *addrPortValue) String() string   { return string(p.AppendTo(nil)) }
+func (p *addrPortValue) AppendTo(dest []byte) []byte {
+	text, _ := netip.AddrPort(*p).MarshalText()
+	return append(dest, text...)
+}
+func (p *addrPortValue) Set(s string) error {
+	var ap netip.AddrPort
+	if err := ap.UnmarshalText([]byte(s)); err != nil {
+		return expected("IP address and port like 192.0.2.1:80 or [2001:db8::1]:80", s, nil)
+	}
+	*p = addrPortValue(ap)
+	return nil
+}
+
+type urlValue url.URL
+
+func (p *urlValue) Get() interface{} { return url.URL(*p) }
+func (p *urlValue) String() string   { return (*url.URL)(p).String() }
+func (p *urlValue) AppendTo(dest []byte) []byte {
+	return append(dest, (*url.URL)(p).String()...)
+}
+func (p *urlValue) Set(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		if uerr, ok := err.(*url.Error); ok {
+			err = uerr.Err
+		}
+		return expected("URL like https://example.com/path", s, err)
+	}
+	*p = urlValue(*u)
+	return nil
+}
+
+type hostPortValue HostPort
+
+func (p *hostPortValue) Get() interface{} { return HostPort(*p) }
+func (p *hostPortValue) String() string   { return string(*p) }
+func (p *hostPortValue) AppendTo(dest []byte) []byte {
+	return append(dest, *p...)
+}
+func (p *hostPortValue) Set(s string) error {
+	const what = "host:port like localhost:8080, :8080 or [::1]:8080"
+	_, port, err := net.SplitHostPort(s)
+	if err != nil {
+		if aerr, ok := err.(*net.AddrError); ok {
+			err = errors.New(aerr.Err)
+		}
+		return expected(what, s, err)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return expected(what, s, errors.New("port must be a number between 0 and 65535"))
+	}
+	*p = hostPortValue(s)
+	return nil
+}
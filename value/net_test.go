@@ -0,0 +1,67 @@
+package value
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValueOfNet(t *testing.T) {
+	tests := []struct {
+		ptr interface{}
+		ok  []string
+		bad string
+		msg string
+	}{
+		{new(net.IP), []string{"192.0.2.1", "2001:db8::1", ""}, "192.0.2", "IP address"},
+		{new(net.IPNet), []string{"192.0.2.0/24", "2001:db8::/32", ""}, "192.0.2.0", "CIDR"},
+		{new(netip.Addr), []string{"192.0.2.1", "::1", ""}, "example.com", "IP address"},
+		{new(netip.Prefix), []string{"192.0.2.0/24", "::/0", ""}, "192.0.2.0/33", "CIDR"},
+		{new(netip.AddrPort), []string{"192.0.2.1:80", "[::1]:443", ""}, "192.0.2.1", "IP address and port"},
+		{new(url.URL), []string{"https://user@example.com/a?b=c#d", "/relative", ""}, "http://[::1", "URL"},
+		{new(HostPort), []string{"localhost:8080", ":80", "[::1]:0"}, "localhost:http", "port must be a number"},
+	}
+	for _, test := range tests {
+		v, ok := ValueOf(test.ptr)
+		if !ok {
+			t.Fatalf("ValueOf([%T]) failed", test.ptr)
+		}
+		for _, s := range test.ok {
+			if err := v.Set(s); err != nil {
+				t.Errorf("%T Set(%q): %s", test.ptr, s, err)
+				continue
+			}
+			if got := v.String(); got != s {
+				t.Errorf("%T: expected %q, got %q", test.ptr, s, got)
+			}
+		}
+		err := v.Set(test.bad)
+		if err == nil {
+			t.Errorf("%T Set(%q) must cause an error", test.ptr, test.bad)
+		} else if !strings.Contains(err.Error(), test.msg) || !strings.Contains(err.Error(), test.bad) {
+			t.Errorf("%T: error %q must explain the expected input", test.ptr, err)
+		}
+	}
+
+	for _, s := range []string{"localhost", "localhost:65536", "a:b:80"} {
+		hp := HostPort("")
+		v, _ := ValueOf(&hp)
+		if err := v.Set(s); err == nil {
+			t.Errorf("HostPort Set(%q) must cause an error", s)
+		}
+	}
+	hp := HostPort("[::1]:8080")
+	if hp.Host() != "::1" || hp.Port() != 8080 {
+		t.Errorf("unexpected host %q and port %d", hp.Host(), hp.Port())
+	}
+
+	ip := net.IPv4(192, 0, 2, 1)
+	v, _ := ValueOf(&ip)
+	got := v.Get().(net.IP)
+	got[len(got)-1] = 2
+	if ip[len(ip)-1] != 1 {
+		t.Errorf("Get must retrieve a copy")
+	}
+}
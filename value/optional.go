@@ -0,0 +1,199 @@
+package value
+
+import "reflect"
+
+// Unsettable is implemented by values distinguishing unset values
+// from zero values.
+type Unsettable interface {
+	Value
+
+	// IsSet reports whether the value is set.
+	IsSet() bool
+}
+
+// Optional is a value of type T that may be unset.
+// T must be supported by ValueOf. The zero Optional is unset.
+// Values of Optionals retrieved by a Registry support the types of
+// the registry.
+//
+// A pointer to an Optional is a Value. Set sets the value and marks it as
+// set even if it is the zero value of T; String retrieves an empty string
+// if the value is not set.
+type Optional[T any] struct {
+	v   T
+	set bool
+}
+
+// Some retrieves an Optional set to v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{v: v, set: true}
+}
+
+// Lookup retrieves the value and reports whether it is set.
+func (o Optional[T]) Lookup() (T, bool) { return o.v, o.set }
+
+// Or retrieves the value if it is set and def otherwise.
+func (o Optional[T]) Or(def T) T {
+	if o.set {
+		return o.v
+	}
+	return def
+}
+
+// IsSet reports whether the value is set.
+func (o *Optional[T]) IsSet() bool { return o.set }
+
+// Unset unsets the value.
+func (o *Optional[T]) Unset() { *o = Optional[T]{} }
+
+// Get retrieves the value, the zero value of T if it is not set.
+func (o *Optional[T]) Get() interface{} { return o.v }
+
+func (o *Optional[T]) String() string { return string(o.AppendTo(nil)) }
+
+func (o *Optional[T]) AppendTo(dest []byte) []byte {
+	return (&optionalValue[T]{o: o, reg: global}).AppendTo(dest)
+}
+
+func (o *Optional[T]) Set(s string) error {
+	return (&optionalValue[T]{o: o, reg: global}).Set(s)
+}
+
+// IsBoolFlag reports whether T is a boolean type.
+func (o *Optional[T]) IsBoolFlag() bool {
+	return (&optionalValue[T]{o: o, reg: global}).IsBoolFlag()
+}
+
+// withRegistry retrieves a value of o creating values of T with r.
+func (o *Optional[T]) withRegistry(r *Registry) Value {
+	return &optionalValue[T]{o: o, reg: r}
+}
+
+// optionalValue is a value of an Optional creating values of T with reg.
+type optionalValue[T any] struct {
+	o   *Optional[T]
+	reg *Registry
+}
+
+func (p *optionalValue[T]) IsSet() bool      { return p.o.set }
+func (p *optionalValue[T]) Get() interface{} { return p.o.v }
+func (p *optionalValue[T]) String() string   { return string(p.AppendTo(nil)) }
+
+func (p *optionalValue[T]) AppendTo(dest []byte) []byte {
+	if !p.o.set {
+		return dest
+	}
+	// p.o.v is copied; AppendTo must not modify p.o
+	v := p.o.v
+	if val, ok := p.reg.ValueOf(&v); ok {
+		return val.AppendTo(dest)
+	}
+	return dest
+}
+
+func (p *optionalValue[T]) Set(s string) error {
+	var v T
+	val, ok := p.reg.ValueOf(&v)
+	if !ok {
+		return expected("value of a supported type", s, nil)
+	}
+	if err := val.Set(s); err != nil {
+		return err
+	}
+	p.o.v, p.o.set = v, true
+	return nil
+}
+
+func (p *optionalValue[T]) IsBoolFlag() bool {
+	var v T
+	val, _ := p.reg.ValueOf(&v)
+	b, ok := val.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func (p *optionalValue[T]) Copy() Value {
+	o := *p.o
+	return &optionalValue[T]{o: &o, reg: p.reg}
+}
+
+// PointerOf retrieves a value of the pointer referenced by ptr,
+// e.g. of a *int referenced by a **int. The pointer is unset while it is nil.
+//
+// Set allocates a new value the pointer refers to. valueOf creates
+// values of the allocated pointers; it must support the referenced type.
+// ValueOf is used if valueOf is nil.
+//
+// Get retrieves the referenced value, or its zero value if the pointer is nil.
+func PointerOf(ptr interface{}, valueOf func(ptr interface{}) (Value, bool)) (Unsettable, bool) {
+	if valueOf == nil {
+		valueOf = ValueOf
+	}
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Ptr {
+		return nil, false
+	}
+	probe, ok := valueOf(reflect.New(rv.Type().Elem().Elem()).Interface())
+	if !ok {
+		return nil, false
+	}
+	b, isBool := probe.(interface{ IsBoolFlag() bool })
+	return &pointerValue{
+		ptr:     rv,
+		valueOf: valueOf,
+		isBool:  isBool && b.IsBoolFlag(),
+	}, true
+}
+
+type pointerValue struct {
+	// ptr is the pointer to the pointer.
+	ptr     reflect.Value
+	valueOf func(ptr interface{}) (Value, bool)
+	isBool  bool
+}
+
+func (p *pointerValue) IsSet() bool      { return !p.ptr.Elem().IsNil() }
+func (p *pointerValue) IsBoolFlag() bool { return p.isBool }
+
+// elem retrieves the value the pointer refers to.
+func (p *pointerValue) elem() (Value, bool) {
+	if !p.IsSet() {
+		return nil, false
+	}
+	return p.valueOf(p.ptr.Elem().Interface())
+}
+
+func (p *pointerValue) Get() interface{} {
+	if val, ok := p.elem(); ok {
+		return val.Get()
+	}
+	return reflect.Zero(p.ptr.Type().Elem().Elem()).Interface()
+}
+
+func (p *pointerValue) String() string { return string(p.AppendTo(nil)) }
+
+func (p *pointerValue) AppendTo(dest []byte) []byte {
+	if val, ok := p.elem(); ok {
+		return val.AppendTo(dest)
+	}
+	return dest
+}
+
+func (p *pointerValue) Set(s string) error {
+	elem := reflect.New(p.ptr.Type().Elem().Elem())
+	val, ok := p.valueOf(elem.Interface())
+	if !ok {
+		return expected("value of a supported type", s, nil)
+	}
+	if err := val.Set(s); err != nil {
+		return err
+	}
+	p.ptr.Elem().Set(elem)
+	return nil
+}
+
+func (p *pointerValue) Copy() Value {
+	c := *p
+	c.ptr = reflect.New(p.ptr.Type().Elem())
+	c.ptr.Elem().Set(p.ptr.Elem())
+	return &c
+}
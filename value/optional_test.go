@@ -0,0 +1,80 @@
+package value
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOptional(t *testing.T) {
+	var o Optional[int]
+	var v Value = &o
+	if u, ok := v.(Unsettable); !ok || u.IsSet() {
+		t.Fatalf("zero Optional must be unset")
+	}
+	if s := v.String(); s != "" {
+		t.Errorf("unset Optional must be empty, got %q", s)
+	}
+	if got := o.Or(5); got != 5 {
+		t.Errorf("expected default 5, got %d", got)
+	}
+	if err := v.Set("0"); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := o.Lookup(); !ok || n != 0 || v.String() != "0" || v.Get() != 0 {
+		t.Errorf("expected set zero value, got %d, %v", n, ok)
+	}
+	if err := v.Set("x"); err == nil || !o.IsSet() {
+		t.Errorf("failed Set must not modify the value")
+	}
+	o.Unset()
+	if o.IsSet() {
+		t.Errorf("Unset must unset the value")
+	}
+
+	b := Some(false)
+	if !b.IsSet() || !b.IsBoolFlag() || b.String() != "false" {
+		t.Errorf("unexpected optional bool %v", b)
+	}
+	var d Optional[time.Duration]
+	if d.IsBoolFlag() {
+		t.Errorf("optional durations are not bool flags")
+	}
+}
+
+func TestPointerOf(t *testing.T) {
+	var timeout *time.Duration
+	v, ok := PointerOf(&timeout, nil)
+	if !ok {
+		t.Fatal("PointerOf([**time.Duration]) failed")
+	}
+	if v.IsSet() || v.String() != "" || v.Get() != time.Duration(0) {
+		t.Errorf("nil pointer must be unset")
+	}
+	c := v.(Copier).Copy()
+	if err := c.Set("1s"); err != nil || timeout != nil {
+		t.Errorf("copy must be independent: %v", err)
+	}
+	if err := v.Set("x"); err == nil || timeout != nil {
+		t.Errorf("failed Set must not allocate")
+	}
+	if err := v.Set("0s"); err != nil {
+		t.Fatal(err)
+	}
+	if timeout == nil || *timeout != 0 || !v.IsSet() || v.String() != "0s" {
+		t.Errorf("expected allocated zero duration")
+	}
+	prev := timeout
+	if err := v.Set("1m"); err != nil || *timeout != time.Minute || *prev != 0 {
+		t.Errorf("Set must allocate a new value")
+	}
+
+	var flag *bool
+	if v, _ := PointerOf(&flag, nil); !v.(interface{ IsBoolFlag() bool }).IsBoolFlag() {
+		t.Errorf("pointers to bools must be bool flags")
+	}
+	for _, ptr := range []interface{}{timeout, &[]int{}, new(*struct{})} {
+		if _, ok := PointerOf(ptr, nil); ok {
+			t.Errorf("PointerOf([%T]) must fail", ptr)
+		}
+	}
+}
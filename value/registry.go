@@ -0,0 +1,101 @@
+package value
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Registry maps types to functions creating values.
+// It is safe for concurrent use. The zero Registry is empty;
+// like registries created with NewRegistry, it supports the types
+// registered with the function Register.
+type Registry struct {
+	mu    sync.RWMutex
+	funcs map[reflect.Type]func(ptr interface{}) Value
+	// parent is consulted for types not registered in the registry,
+	// global if nil.
+	parent *Registry
+}
+
+// global is the registry used by ValueOf.
+var global = &Registry{}
+
+// NewRegistry creates an empty registry. Types not registered in it
+// are looked up in the types registered with the function Register.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// registered is implemented by values creating values of other types,
+// they use the registry retrieving them.
+type registered interface {
+	withRegistry(r *Registry) Value
+}
+
+// Register registers fn to create values referenced by pointers to t
+// for ValueOf and all registries.
+// It replaces functions previously registered for t.
+func Register(t reflect.Type, fn func(ptr interface{}) Value) {
+	global.Register(t, fn)
+}
+
+// Register registers fn to create values referenced by pointers to t.
+// If fn returns nil, the value is created as if t was not registered.
+// Register replaces functions previously registered for t,
+// a nil fn removes the registration.
+func (r *Registry) Register(t reflect.Type, fn func(ptr interface{}) Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fn == nil {
+		delete(r.funcs, t)
+		return
+	}
+	if r.funcs == nil {
+		r.funcs = make(map[reflect.Type]func(ptr interface{}) Value)
+	}
+	r.funcs[t] = fn
+}
+
+// lookup retrieves the function registered for t in r or its parents.
+func (r *Registry) lookup(t reflect.Type) func(ptr interface{}) Value {
+	for ; r != nil; r = r.next() {
+		r.mu.RLock()
+		fn := r.funcs[t]
+		r.mu.RUnlock()
+		if fn != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// next retrieves the registry consulted after r.
+func (r *Registry) next() *Registry {
+	if r.parent == nil && r != global {
+		return global
+	}
+	return r.parent
+}
+
+// ValueOf retrieves a value referenced by ptr like the function ValueOf,
+// but registered types are looked up in r first.
+// Elements of slices and maps and values of Optionals are also created by r.
+func (r *Registry) ValueOf(ptr interface{}) (Value, bool) {
+	if ptr == nil {
+		return nil, false
+	}
+	if t := reflect.TypeOf(ptr); t.Kind() == reflect.Ptr && !reflect.ValueOf(ptr).IsNil() {
+		if fn := r.lookup(t.Elem()); fn != nil {
+			if val := fn(ptr); val != nil {
+				return val, true
+			}
+		}
+	}
+	if val, ok := ptr.(Value); ok {
+		if reg, ok := val.(registered); ok {
+			return reg.withRegistry(r), true
+		}
+		return val, true
+	}
+	return r.builtin(ptr)
+}
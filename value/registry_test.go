@@ -0,0 +1,110 @@
+package value
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// point is a type without builtin support.
+type point struct {
+	X, Y int
+}
+
+type pointValue point
+
+func (p *pointValue) Get() interface{} { return point(*p) }
+func (p *pointValue) String() string   { return string(p.AppendTo(nil)) }
+func (p *pointValue) AppendTo(dest []byte) []byte {
+	dest = append(dest, "("...)
+	dest = append(dest, (*intValue)(&p.X).String()...)
+	dest = append(dest, ' ')
+	dest = append(dest, (*intValue)(&p.Y).String()...)
+	return append(dest, ')')
+}
+func (p *pointValue) Set(s string) error {
+	xy := strings.Fields(strings.Trim(s, "()"))
+	if len(xy) != 2 {
+		return expected("point like (1 2)", s, nil)
+	}
+	if err := (*intValue)(&p.X).Set(xy[0]); err != nil {
+		return err
+	}
+	return (*intValue)(&p.Y).Set(xy[1])
+}
+
+func TestRegistry(t *testing.T) {
+	typ := reflect.TypeOf(point{})
+	newPoint := func(ptr interface{}) Value {
+		return (*pointValue)(ptr.(*point))
+	}
+	var p point
+	if _, ok := ValueOf(&p); ok {
+		t.Fatalf("point must not be supported before registration")
+	}
+
+	r := NewRegistry()
+	r.Register(typ, newPoint)
+	if _, ok := ValueOf(&p); ok {
+		t.Errorf("types registered in a registry must not be supported by ValueOf")
+	}
+	var ps []point
+	v, ok := r.ValueOf(&ps)
+	if !ok {
+		t.Fatalf("slices of registered types must be supported")
+	}
+	if err := v.Set(`(1 2),(3 4)`); err != nil || len(ps) != 2 || ps[1].Y != 4 {
+		t.Errorf("unexpected points %v: %v", ps, err)
+	}
+
+	// optionals create values with the registry retrieving them
+	var o Optional[point]
+	if err := o.Set("(1 2)"); err == nil {
+		t.Errorf("Optional must not support types registered in a registry")
+	}
+	v, ok = r.ValueOf(&o)
+	if !ok {
+		t.Fatalf("optionals of registered types must be supported")
+	}
+	if err := v.Set("(7 8)"); err != nil || !o.IsSet() || o.v.Y != 8 || v.String() != "(7 8)" {
+		t.Errorf("unexpected optional %v: %v", o, err)
+	}
+	if c := v.(Copier).Copy(); c.Set("(9 9)") != nil || o.v.X != 7 {
+		t.Errorf("copies must not modify the optional")
+	}
+
+	Register(typ, newPoint)
+	defer Register(typ, nil)
+	if v, ok := ValueOf(&p); !ok || v.Set("(5 6)") != nil || p.X != 5 {
+		t.Errorf("registered type must be supported by ValueOf")
+	}
+	if _, ok := NewRegistry().ValueOf(&p); !ok {
+		t.Errorf("registries must support globally registered types")
+	}
+	if _, ok := (&Registry{}).ValueOf(&p); !ok {
+		t.Errorf("zero registries must support globally registered types")
+	}
+
+	// registered types take precedence
+	r.Register(reflect.TypeOf(0), func(ptr interface{}) Value {
+		return EnumOf(ptr.(*int), map[string]int{"one": 1}, false)
+	})
+	i := 1
+	if v, _ := r.ValueOf(&i); v.String() != "one" {
+		t.Errorf("expected registered int value, got %s", v)
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				r.Register(typ, newPoint)
+				r.ValueOf(&p)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,137 @@
+package value
+
+import (
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes.
+//
+// Sizes are represented as numbers with an optional unit, e.g. "512",
+// "10KB", "10KiB" or "1.5GiB". SI units (KB, MB, GB, TB, PB, EB) are powers
+// of 1000, IEC units (KiB, MiB, GiB, TiB, PiB, EiB) are powers of 1024.
+// Units are case-insensitive and may be separated from the number by spaces.
+// Fractions are allowed if the result is a whole number of bytes.
+type ByteSize uint64
+
+// byteUnits contains the units of byte sizes, ordered by size.
+var byteUnits = []struct {
+	name string
+	size uint64
+}{
+	{"B", 1},
+	{"KB", 1e3},
+	{"KiB", 1 << 10},
+	{"MB", 1e6},
+	{"MiB", 1 << 20},
+	{"GB", 1e9},
+	{"GiB", 1 << 30},
+	{"TB", 1e12},
+	{"TiB", 1 << 40},
+	{"PB", 1e15},
+	{"PiB", 1 << 50},
+	{"EB", 1e18},
+	{"EiB", 1 << 60},
+}
+
+const (
+	sizeSyntax = "byte size like 512, 10KB, 10KiB or 1.5GiB"
+	maxSize    = "byte size of at most 18446744073709551615B"
+)
+
+// String formats the size with the largest unit dividing it.
+func (b ByteSize) String() string {
+	return string(b.AppendTo(nil))
+}
+
+// AppendTo appends the formatted size to dest.
+func (b ByteSize) AppendTo(dest []byte) []byte {
+	n := uint64(b)
+	unit := byteUnits[0]
+	for _, u := range byteUnits[1:] {
+		if n != 0 && n%u.size == 0 {
+			unit = u
+		}
+	}
+	dest = strconv.AppendUint(dest, n/unit.size, 10)
+	return append(dest, unit.name...)
+}
+
+// ParseByteSize parses the representation of a byte size.
+func ParseByteSize(s string) (ByteSize, error) {
+	num := strings.TrimSpace(s)
+	end := strings.IndexFunc(num, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	unit := byteUnits[0]
+	if end >= 0 {
+		name := strings.TrimSpace(num[end:])
+		num = num[:end]
+		found := false
+		for _, u := range byteUnits {
+			if strings.EqualFold(name, u.name) {
+				unit, found = u, true
+				break
+			}
+		}
+		if !found {
+			return 0, expected(sizeSyntax, s, errors.New("unknown unit "+strconv.Quote(name)))
+		}
+	}
+	whole, frac := num, ""
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		whole, frac = num[:i], num[i+1:]
+	}
+	if whole == "" && frac == "" {
+		return 0, expected(sizeSyntax, s, nil)
+	}
+	w, err := strconv.ParseUint("0"+whole, 10, 64)
+	if err != nil {
+		return 0, expected(sizeSyntax, s, nil)
+	}
+	hi, n := bits.Mul64(w, unit.size)
+	if hi != 0 {
+		return 0, expected(maxSize, s, nil)
+	}
+	if frac = strings.TrimRight(frac, "0"); frac != "" {
+		// fraction of the unit, must be a whole number of bytes
+		f, err := strconv.ParseUint(frac, 10, 64)
+		if err != nil || len(frac) > 19 {
+			return 0, expected(sizeSyntax, s, nil)
+		}
+		var div uint64 = 1
+		for range frac {
+			div *= 10
+		}
+		fhi, flo := bits.Mul64(f, unit.size)
+		if fhi >= div {
+			return 0, expected(maxSize, s, nil)
+		}
+		q, r := bits.Div64(fhi, flo, div)
+		if r != 0 {
+			return 0, expected("whole number of bytes", s, nil)
+		}
+		var carry uint64
+		if n, carry = bits.Add64(n, q, 0); carry != 0 {
+			return 0, expected(maxSize, s, nil)
+		}
+	}
+	return ByteSize(n), nil
+}
+
+type byteSizeValue ByteSize
+
+func (p *byteSizeValue) Get() interface{} { return ByteSize(*p) }
+func (p *byteSizeValue) String() string   { return ByteSize(*p).String() }
+func (p *byteSizeValue) AppendTo(dest []byte) []byte {
+	return ByteSize(*p).AppendTo(dest)
+}
+func (p *byteSizeValue) Set(s string) error {
+	b, err := ParseByteSize(s)
+	if err == nil {
+		*p = byteSizeValue(b)
+	}
+	return err
+}
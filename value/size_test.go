@@ -0,0 +1,56 @@
+package value
+
+import "testing"
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+		str  string
+	}{
+		{"512", 512, "512B"},
+		{"0", 0, "0B"},
+		{"10KB", 10000, "10KB"},
+		{"10kib", 10240, "10KiB"},
+		{"1.5GiB", 3 << 29, "1536MiB"},
+		{"1.5 MB", 1500000, "1500KB"},
+		{" 2048000 ", 2048000, "2000KiB"},
+		{".5KiB", 512, "512B"},
+		{"1.000B", 1, "1B"},
+		{"16EiB", 0, ""},
+		{"15EiB", 15 << 60, "15EiB"},
+		{"18446744073709551615", 1<<64 - 1, "18446744073709551615B"},
+	}
+	for _, test := range tests {
+		b, err := ParseByteSize(test.in)
+		if test.str == "" {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %d", test.in, b)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", test.in, err)
+			continue
+		}
+		if b != test.want {
+			t.Errorf("%q: expected %d, got %d", test.in, test.want, b)
+		}
+		if s := b.String(); s != test.str {
+			t.Errorf("%q: expected %q, got %q", test.in, test.str, s)
+		}
+		if rt, err := ParseByteSize(b.String()); err != nil || rt != b {
+			t.Errorf("%q: %s does not round-trip", test.in, b)
+		}
+	}
+	for _, s := range []string{"", "KB", "1.5B", "1XB", "-1", "1.2.3KB", "0.0000000001KB"} {
+		if _, err := ParseByteSize(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestValueOfByteSize(t *testing.T) {
+	b := ByteSize(0)
+	testValidCases(t, &b, ByteSize(1024), ByteSize(5000), ByteSize(7))
+}
@@ -0,0 +1,150 @@
+package value
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// List is implemented by values of lists.
+//
+// Joining the representations of two lists with the separator results
+// in the representation of the concatenated list. When a list is bound
+// to a flag, repeated flags are joined this way.
+type List interface {
+	Value
+	Separator() rune
+}
+
+// Copier is implemented by values referencing memory that is not copied
+// with them. Copy retrieves a Value referencing an independent copy.
+type Copier interface {
+	Copy() Value
+}
+
+// SliceOf retrieves a value of the slice referenced by ptr.
+// The slice elements must be supported by ValueOf and not be slices themselves.
+// Slices of bytes are not supported.
+//
+// The representation is a list of elements separated by sep.
+// If quote is true, elements can be quoted as in CSV files, e.g. `"a,b",c`
+// contains the elements "a,b" and "c". Elements are quoted as required.
+// Without quoting, a single empty element cannot be distinguished from
+// an empty list, both are represented by an empty string.
+//
+// Set replaces the slice, the elements of the previous slice are not modified.
+func SliceOf(ptr interface{}, sep rune, quote bool) (Value, bool) {
+	return global.SliceOf(ptr, sep, quote)
+}
+
+// SliceOf is like the function SliceOf, elements are supported
+// if they are supported by r.ValueOf.
+func (r *Registry) SliceOf(ptr interface{}, sep rune, quote bool) (Value, bool) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return nil, false
+	}
+	elem := rv.Type().Elem().Elem()
+	if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	if _, ok := r.ValueOf(reflect.New(elem).Interface()); !ok {
+		return nil, false
+	}
+	if sep == 0 || sep == '"' || sep == '\r' || sep == '\n' {
+		return nil, false
+	}
+	return &sliceValue{ptr: rv, sep: sep, quote: quote, reg: r}, true
+}
+
+type sliceValue struct {
+	// ptr is the pointer to the slice.
+	ptr   reflect.Value
+	sep   rune
+	quote bool
+	// reg provides values of the elements.
+	reg *Registry
+}
+
+func (p *sliceValue) Separator() rune { return p.sep }
+
+func (p *sliceValue) Copy() Value {
+	c := *p
+	c.ptr = reflect.New(p.ptr.Type().Elem())
+	c.ptr.Elem().Set(p.ptr.Elem())
+	return &c
+}
+
+func (p *sliceValue) Get() interface{} {
+	s := p.ptr.Elem()
+	if s.IsNil() {
+		return s.Interface()
+	}
+	c := reflect.MakeSlice(s.Type(), s.Len(), s.Len())
+	reflect.Copy(c, s)
+	return c.Interface()
+}
+
+func (p *sliceValue) String() string {
+	return string(p.AppendTo(nil))
+}
+
+// fields retrieves the representations of the elements.
+func (p *sliceValue) fields() []string {
+	s := p.ptr.Elem()
+	fields := make([]string, s.Len())
+	for i := range fields {
+		v, _ := p.reg.ValueOf(s.Index(i).Addr().Interface())
+		fields[i] = v.String()
+	}
+	return fields
+}
+
+func (p *sliceValue) AppendTo(dest []byte) []byte {
+	fields := p.fields()
+	if !p.quote {
+		return append(dest, strings.Join(fields, string(p.sep))...)
+	}
+	if len(fields) == 1 && fields[0] == "" {
+		// distinguish a single empty element from an empty list
+		return append(dest, `""`...)
+	}
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Comma = p.sep
+	w.Write(fields)
+	w.Flush()
+	return append(dest, strings.TrimSuffix(b.String(), "\n")...)
+}
+
+func (p *sliceValue) Set(s string) error {
+	var fields []string
+	switch {
+	case s == "":
+	case !p.quote:
+		fields = strings.Split(s, string(p.sep))
+	default:
+		r := csv.NewReader(strings.NewReader(s))
+		r.Comma = p.sep
+		r.FieldsPerRecord = -1
+		var err error
+		if fields, err = r.Read(); err != nil {
+			return err
+		}
+		if _, err := r.Read(); err != io.EOF {
+			return errors.New("value: unexpected line break in list")
+		}
+	}
+	typ := p.ptr.Type().Elem()
+	slice := reflect.MakeSlice(typ, len(fields), len(fields))
+	for i, field := range fields {
+		v, _ := p.reg.ValueOf(slice.Index(i).Addr().Interface())
+		if err := v.Set(field); err != nil {
+			return err
+		}
+	}
+	p.ptr.Elem().Set(slice)
+	return nil
+}
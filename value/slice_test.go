@@ -0,0 +1,91 @@
+package value
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValueOfSlice(t *testing.T) {
+	tests := []struct {
+		ptr  interface{}
+		set  string
+		want interface{}
+		str  string
+	}{
+		{new([]string), "", []string{}, ""},
+		{new([]string), `a,"b,c", d`, []string{"a", "b,c", " d"}, `a,"b,c"," d"`},
+		{new([]string), `"a ""b"""`, []string{`a "b"`}, `"a ""b"""`},
+		{new([]string), `""`, []string{""}, `""`},
+		{new([]string), ",", []string{"", ""}, ","},
+		{new([]int), "1,0x10,-3", []int{1, 16, -3}, "1,16,-3"},
+		{new([]time.Duration), "1s,1h", []time.Duration{time.Second, time.Hour}, "1s,1h0m0s"},
+		{new([]bool), "true,0", []bool{true, false}, "true,false"},
+	}
+	for _, test := range tests {
+		v, ok := ValueOf(test.ptr)
+		if !ok {
+			t.Fatalf("ValueOf([%T]) failed", test.ptr)
+		}
+		if err := v.Set(test.set); err != nil {
+			t.Errorf("%T Set(%q): %s", test.ptr, test.set, err)
+			continue
+		}
+		if got := v.Get(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%T Get(): expected %v, got %v", test.ptr, test.want, got)
+		}
+		if got := reflect.ValueOf(test.ptr).Elem().Interface(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%T: expected %v to be set, got %v", test.ptr, test.want, got)
+		}
+		s := v.String()
+		if s != test.str {
+			t.Errorf("%T String(): expected %q, got %q", test.ptr, test.str, s)
+		}
+		// the representation round-trips
+		if err := v.Set(s); err != nil || !reflect.DeepEqual(v.Get(), test.want) {
+			t.Errorf("%T: %q does not round-trip", test.ptr, s)
+		}
+	}
+
+	var ints []int
+	v, _ := ValueOf(&ints)
+	for _, s := range []string{"1,x", `"1`, "1\n2"} {
+		if err := v.Set(s); err == nil {
+			t.Errorf("Set(%q) must cause an error", s)
+		}
+	}
+}
+
+func TestSliceOf(t *testing.T) {
+	s := []string{"a"}
+	v, ok := SliceOf(&s, ';', false)
+	if !ok {
+		t.Fatal("SliceOf failed")
+	}
+	if sep := v.(List).Separator(); sep != ';' {
+		t.Errorf("expected separator ';', got %q", sep)
+	}
+	before := s
+	if err := v.Set(`"x;y`); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, []string{`"x`, "y"}) || before[0] != "a" {
+		t.Errorf("unexpected slices %q, %q", s, before)
+	}
+	c := v.(Copier).Copy()
+	if err := c.Set("z"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 2 || c.String() != "z" {
+		t.Errorf("copy must be independent, got %q and %q", s, c)
+	}
+
+	for _, ptr := range []interface{}{s, &[][]string{}, &[]byte{}, &[]chan int{}} {
+		if _, ok := SliceOf(ptr, ',', true); ok {
+			t.Errorf("SliceOf([%T]) must fail", ptr)
+		}
+	}
+	if _, ok := SliceOf(&s, '"', true); ok {
+		t.Errorf("SliceOf with quote as separator must fail")
+	}
+}
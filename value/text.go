@@ -0,0 +1,79 @@
+package value
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// TextOf retrieves a value referenced by ptr if ptr implements
+// encoding.TextUnmarshaler.
+//
+// String and AppendTo use MarshalText if the referenced value or ptr
+// implement encoding.TextMarshaler, String if they implement fmt.Stringer
+// and the default format of the fmt package otherwise.
+func TextOf(ptr interface{}) (Value, bool) {
+	u, ok := ptr.(encoding.TextUnmarshaler)
+	if !ok {
+		return nil, false
+	}
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, false
+	}
+	return &textValue{ptr: rv, u: u}, true
+}
+
+type textValue struct {
+	ptr reflect.Value
+	u   encoding.TextUnmarshaler
+}
+
+func (p *textValue) Get() interface{} { return p.ptr.Elem().Interface() }
+
+func (p *textValue) String() string {
+	return string(p.AppendTo(nil))
+}
+
+func (p *textValue) AppendTo(dest []byte) []byte {
+	for _, v := range []interface{}{p.Get(), p.u} {
+		if m, ok := v.(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return dest
+			}
+			return append(dest, text...)
+		}
+	}
+	for _, v := range []interface{}{p.Get(), p.u} {
+		if s, ok := v.(fmt.Stringer); ok {
+			return append(dest, s.String()...)
+		}
+	}
+	return fmt.Append(dest, p.Get())
+}
+
+func (p *textValue) Set(s string) error {
+	// failed parses must not leave partially set values
+	ptr := reflect.New(p.ptr.Type().Elem())
+	if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+	p.ptr.Elem().Set(ptr.Elem())
+	return nil
+}
+
+// Copy retrieves a Value referencing a new value. Its text representation
+// is copied if possible, the referenced value is copied otherwise.
+func (p *textValue) Copy() Value {
+	ptr := reflect.New(p.ptr.Type().Elem())
+	c := &textValue{ptr: ptr, u: ptr.Interface().(encoding.TextUnmarshaler)}
+	if m, ok := p.u.(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil && c.u.UnmarshalText(text) == nil {
+			return c
+		}
+		ptr.Elem().Set(reflect.Zero(ptr.Type().Elem()))
+	}
+	ptr.Elem().Set(p.ptr.Elem())
+	return c
+}
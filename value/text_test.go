@@ -0,0 +1,81 @@
+package value
+
+import (
+	"math/big"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+// upper is a TextUnmarshaler without TextMarshaler or String methods.
+type upper struct {
+	s string
+}
+
+func (u *upper) UnmarshalText(text []byte) error {
+	u.s = strings.ToUpper(string(text))
+	return nil
+}
+
+func TestValueOfText(t *testing.T) {
+	addr := netip.Addr{}
+	v, ok := ValueOf(&addr)
+	if !ok {
+		t.Fatal("ValueOf([*netip.Addr]) failed")
+	}
+	if err := v.Set("192.0.2.1"); err != nil {
+		t.Fatal(err)
+	}
+	if addr != netip.MustParseAddr("192.0.2.1") || v.Get() != addr || v.String() != "192.0.2.1" {
+		t.Errorf("unexpected address %s", v)
+	}
+	if err := v.Set("x"); err == nil {
+		t.Errorf("Set(%q) must cause an error", "x")
+	}
+
+	i := big.NewInt(1)
+	v, ok = ValueOf(i)
+	if !ok {
+		t.Fatal("ValueOf([*big.Int]) failed")
+	}
+	c := v.(Copier).Copy()
+	if err := c.Set("123456789012345678901234567890"); err != nil {
+		t.Fatal(err)
+	}
+	if i.Int64() != 1 || c.String() != "123456789012345678901234567890" {
+		t.Errorf("copy must be independent, got %s and %s", i, c)
+	}
+
+	// failed parses leave the value unchanged
+	f := big.NewFloat(1.5)
+	v, _ = ValueOf(f)
+	if err := v.Set("2.5x"); err == nil || f.String() != "1.5" {
+		t.Errorf("failed parse must not modify the value, got %s: %v", f, err)
+	}
+	r := big.NewRat(3, 1)
+	v, _ = ValueOf(r)
+	if err := v.Set("1/0"); err == nil || r.String() != "3/1" {
+		t.Errorf("failed parse must not modify the value, got %s: %v", r, err)
+	}
+
+	u := upper{}
+	v, ok = ValueOf(&u)
+	if !ok {
+		t.Fatal("ValueOf([*upper]) failed")
+	}
+	if err := v.Set("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(v.AppendTo(nil)); got != "{ABC}" {
+		t.Errorf("expected default format {ABC}, got %s", got)
+	}
+
+	var addrs []netip.Addr
+	v, ok = ValueOf(&addrs)
+	if !ok {
+		t.Fatal("ValueOf([*[]netip.Addr]) failed")
+	}
+	if err := v.Set("::1,192.0.2.1"); err != nil || len(addrs) != 2 {
+		t.Errorf("unexpected addresses %v: %v", addrs, err)
+	}
+}
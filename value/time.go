@@ -0,0 +1,85 @@
+package value
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Layouts for TimeOf representing times as Unix timestamps.
+const (
+	// Unix represents times as seconds since January 1, 1970 UTC.
+	Unix = "unix"
+
+	// UnixMilli represents times as milliseconds since January 1, 1970 UTC.
+	UnixMilli = "unixmilli"
+)
+
+// TimeOf retrieves a value of the time referenced by ptr.
+//
+// Times are parsed and formatted with layout as in the time package,
+// or as Unix timestamps if layout is Unix or UnixMilli.
+// If layout is empty, time.RFC3339Nano is used, which also parses
+// RFC 3339 times without fractional seconds.
+//
+// Times without time zone information are parsed in loc.
+// Times are formatted in loc. If loc is nil, UTC is used.
+func TimeOf(ptr *time.Time, layout string, loc *time.Location) Value {
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &timeValue{ptr: ptr, layout: layout, loc: loc}
+}
+
+type timeValue struct {
+	ptr    *time.Time
+	layout string
+	loc    *time.Location
+}
+
+func (p *timeValue) Get() interface{} { return *p.ptr }
+func (p *timeValue) String() string   { return string(p.AppendTo(nil)) }
+
+func (p *timeValue) AppendTo(dest []byte) []byte {
+	t := p.ptr.In(p.loc)
+	switch p.layout {
+	case Unix:
+		return strconv.AppendInt(dest, t.Unix(), 10)
+	case UnixMilli:
+		return strconv.AppendInt(dest, t.UnixMilli(), 10)
+	}
+	return t.AppendFormat(dest, p.layout)
+}
+
+func (p *timeValue) Set(s string) error {
+	var t time.Time
+	switch p.layout {
+	case Unix, UnixMilli:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return errors.New("value: expected " + p.layout + " timestamp, got " + strconv.Quote(s))
+		}
+		if p.layout == Unix {
+			t = time.Unix(n, 0)
+		} else {
+			t = time.UnixMilli(n)
+		}
+	default:
+		var err error
+		if t, err = time.ParseInLocation(p.layout, s, p.loc); err != nil {
+			return err
+		}
+	}
+	*p.ptr = t.In(p.loc)
+	return nil
+}
+
+func (p *timeValue) Copy() Value {
+	c := *p
+	c.ptr = new(time.Time)
+	*c.ptr = *p.ptr
+	return &c
+}
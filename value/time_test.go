@@ -0,0 +1,74 @@
+package value
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValueOfTime(t *testing.T) {
+	var tm time.Time
+	v, ok := ValueOf(&tm)
+	if !ok {
+		t.Fatal("ValueOf([*time.Time]) failed")
+	}
+	if err := v.Set("2024-03-01T12:30:00+01:00"); err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 3, 1, 11, 30, 0, 0, time.UTC)
+	if !tm.Equal(want) || v.Get().(time.Time) != tm {
+		t.Errorf("expected %s, got %s", want, tm)
+	}
+	if s := v.String(); s != "2024-03-01T11:30:00Z" {
+		t.Errorf("unexpected representation %s", s)
+	}
+	if err := v.Set("2024-03-01T11:30:00.123456789Z"); err != nil || v.String() != "2024-03-01T11:30:00.123456789Z" {
+		t.Errorf("fractional seconds must round-trip, got %s: %v", v, err)
+	}
+	if err := v.Set("2024-03-01"); err == nil {
+		t.Errorf("Set must fail on wrong layout")
+	}
+}
+
+func TestTimeOf(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		layout string
+		loc    *time.Location
+		set    string
+		want   time.Time
+		str    string
+	}{
+		{"2006-01-02", nil, "2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "2024-03-01"},
+		{"2006-01-02 15:04", berlin, "2024-03-01 12:00", time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC), "2024-03-01 12:00"},
+		{"", berlin, "2024-07-01T00:00:00Z", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), "2024-07-01T02:00:00+02:00"},
+		{Unix, nil, "1700000000", time.Unix(1700000000, 0), "1700000000"},
+		{UnixMilli, nil, "-1500", time.UnixMilli(-1500), "-1500"},
+	}
+	for _, test := range tests {
+		var tm time.Time
+		v := TimeOf(&tm, test.layout, test.loc)
+		if err := v.Set(test.set); err != nil {
+			t.Errorf("%q: %s", test.layout, err)
+			continue
+		}
+		if !tm.Equal(test.want) {
+			t.Errorf("%q: expected %s, got %s", test.layout, test.want, tm)
+		}
+		if s := v.String(); s != test.str {
+			t.Errorf("%q: expected representation %s, got %s", test.layout, test.str, s)
+		}
+	}
+
+	var tm time.Time
+	v := TimeOf(&tm, Unix, nil)
+	if err := v.Set("1.5"); err == nil {
+		t.Errorf("Set must fail on invalid timestamps")
+	}
+	c := v.(Copier).Copy()
+	if err := c.Set("1"); err != nil || !tm.IsZero() {
+		t.Errorf("copy must be independent: %v", err)
+	}
+}
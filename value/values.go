@@ -1,6 +1,9 @@
 package value
 
 import (
+	"net"
+	"net/netip"
+	"net/url"
 	"strconv"
 	"time"
 )
@@ -18,13 +21,27 @@ type Value interface {
 // ValueOf retrieves a value referenced by ptr.
 //
 // The referenced value must be either be an int, uint or float type,
-// or it must be a bool, string, or time.Duration.
+// or it must be a bool, string, time.Duration or time.Time.
+// Times are represented as in RFC 3339, see TimeOf.
+// The network types net.IP, net.IPNet, netip.Addr, netip.Prefix,
+// netip.AddrPort, url.URL and HostPort are supported, as well as ByteSize.
+// Pointers implementing encoding.TextUnmarshaler are supported, see TextOf.
+// Other named types of these kinds are supported, see KindOf.
+// Slices of these types are lists of comma separated elements
+// with CSV-style quoting, see SliceOf. Maps with keys and values
+// of these types are comma separated lists of "key=value", see MapOf.
+// Pointers implementing Value are retrieved as they are.
+//
+// Types registered with Register take precedence over all other types.
 //
 // As in the flag package, IsBoolFlag() returns true for bool values.
 func ValueOf(ptr interface{}) (val Value, ok bool) {
-	if ptr == nil {
-		return nil, false
-	}
+	return global.ValueOf(ptr)
+}
+
+// builtin retrieves a value of a type supported by ValueOf without
+// consulting registries; lists and maps use r for their elements.
+func (r *Registry) builtin(ptr interface{}) (val Value, ok bool) {
 	switch val := ptr.(type) {
 	case *string:
 		return (*stringValue)(val), true
@@ -56,8 +73,35 @@ func ValueOf(ptr interface{}) (val Value, ok bool) {
 		return (*float64Value)(val), true
 	case *time.Duration:
 		return (*durationValue)(val), true
+	case *time.Time:
+		return TimeOf(val, "", nil), true
+	case *net.IP:
+		return (*ipValue)(val), true
+	case *net.IPNet:
+		return (*ipNetValue)(val), true
+	case *netip.Addr:
+		return (*addrValue)(val), true
+	case *netip.Prefix:
+		return (*prefixValue)(val), true
+	case *netip.AddrPort:
+		return (*addrPortValue)(val), true
+	case *url.URL:
+		return (*urlValue)(val), true
+	case *HostPort:
+		return (*hostPortValue)(val), true
+	case *ByteSize:
+		return (*byteSizeValue)(val), true
+	}
+	if val, ok := TextOf(ptr); ok {
+		return val, true
+	}
+	if val, ok := KindOf(ptr); ok {
+		return val, true
+	}
+	if val, ok := r.SliceOf(ptr, ',', true); ok {
+		return val, true
 	}
-	return nil, false
+	return r.MapOf(ptr, ',')
 }
 
 type boolValue bool
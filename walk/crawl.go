@@ -26,15 +26,17 @@ type Pointer interface{}
 // If data is changed while it is crawled, the crawler becomes invalid
 // unless the node that was changed is left and reentered.
 type Crawler struct {
-	path []edge
-	val  reflect.Value
-	size int
+	path     []edge
+	val      reflect.Value
+	size     int
+	embedded bool
 }
 
 // edge leads to a node.
 type edge struct {
-	src reflect.Value
-	key Key
+	src      reflect.Value
+	key      Key
+	embedded bool
 }
 
 // mapKey is a map Key, provided as a fastpath for mapIterator.
@@ -53,13 +55,17 @@ func NewCrawler(ptr Pointer) (c *Crawler, ok bool) {
 	val = val.Elem()
 	return &Crawler{
 		val:  val,
-		size: size(val),
+		size: size(val, false),
 	}, true
 }
 
 // size determines the number of descendant nodes.
-func size(val reflect.Value) int {
-	if !val.CanInterface() {
+//
+// Nodes that can not be interfaced only have descendants if they are
+// embedded, as exported fields of embedded structs with an unexported type
+// are accessible.
+func size(val reflect.Value, embedded bool) int {
+	if !val.CanInterface() && !embedded {
 		return 0
 	}
 	switch val.Kind() {
@@ -90,6 +96,16 @@ func (c *Crawler) Depth() int {
 	return len(c.path)
 }
 
+// Kind retrieves the kind of the current node.
+func (c *Crawler) Kind() reflect.Kind {
+	return c.val.Kind()
+}
+
+// ValueType retrieves the type of the current node.
+func (c *Crawler) ValueType() reflect.Type {
+	return c.val.Type()
+}
+
 // Ordered reports whether numbers in [0, Size) can be used as keys in Enter.
 func (c *Crawler) Ordered() bool {
 	switch c.val.Kind() {
@@ -155,23 +171,31 @@ func (c *Crawler) Enter(key Key) bool {
 			return false
 		}
 	}
+	// embedded fields and their pointer targets
+	embedded := false
+	if sf, ok := key.(reflect.StructField); ok {
+		embedded = sf.Anonymous
+	} else if key == Elem {
+		embedded = c.embedded
+	}
 	c.path = append(
 		c.path,
 		edge{
-			src: c.val,
-			key: key,
+			src:      c.val,
+			key:      key,
+			embedded: c.embedded,
 		},
 	)
-	c.val, c.size = val, size(val)
+	c.val, c.size, c.embedded = val, size(val, embedded), embedded
 	return true
 }
 
 // Leave reverts the last successful Enter.
 func (c *Crawler) Leave() {
 	if end := len(c.path) - 1; end >= 0 {
-		src := c.path[end].src
+		src, embedded := c.path[end].src, c.path[end].embedded
 		c.path = c.path[:end]
-		c.val, c.size = src, size(src)
+		c.val, c.size, c.embedded = src, size(src, embedded), embedded
 	}
 }
 
@@ -255,26 +279,36 @@ func (c *Crawler) AppendPath(dest []byte, full bool) []byte {
 		if i > 0 {
 			dest = append(dest, '/')
 		}
-		switch key := c.Key(i).(type) {
-		case int:
-			dest = strconv.AppendInt(dest, int64(key), 10)
-		case reflect.StructField:
-			if full {
-				base := c.path[i].src.Type().Field(key.Index[0])
+		dest = c.AppendKey(dest, i, full)
+	}
+	return dest
+}
+
+// AppendKey appends the path segment of the key at the given depth.
+//
+// The segment is escaped as in AppendPath. It is empty for unprintable keys.
+//
+// AppendKey panics if no key to a node exists at that depth.
+func (c *Crawler) AppendKey(dest []byte, depth int, full bool) []byte {
+	switch key := c.Key(depth).(type) {
+	case int:
+		dest = strconv.AppendInt(dest, int64(key), 10)
+	case reflect.StructField:
+		if full {
+			base := c.path[depth].src.Type().Field(key.Index[0])
+			dest = append(dest, base.Name...)
+			for _, f := range key.Index[1:] {
+				base = base.Type.Field(f)
+				dest = append(dest, '/')
 				dest = append(dest, base.Name...)
-				for _, f := range key.Index[1:] {
-					base = base.Type.Field(f)
-					dest = append(dest, '/')
-					dest = append(dest, base.Name...)
-				}
-			} else {
-				dest = append(dest, key.Name...)
 			}
-		case string:
-			dest = appendEscaped(dest, key)
-		case stringer:
-			dest = appendEscaped(dest, key.String())
+		} else {
+			dest = append(dest, key.Name...)
 		}
+	case string:
+		dest = appendEscaped(dest, key)
+	case stringer:
+		dest = appendEscaped(dest, key.String())
 	}
 	return dest
 }
@@ -2,6 +2,7 @@ package walk
 
 import (
 	"fmt"
+	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
@@ -652,3 +653,62 @@ func TestCrawlerDontPanicOnBadMapKey(t *testing.T) {
 		t.Fatalf("invalid map key type")
 	}
 }
+
+type embeddedUnexported struct {
+	V int
+}
+
+func TestCrawlerEmbeddedUnexported(t *testing.T) {
+	x := struct {
+		embeddedUnexported
+		p *embeddedUnexported
+	}{
+		p: &embeddedUnexported{},
+	}
+	c, ok := NewCrawler(&x)
+	if !ok {
+		t.Fatalf("could not crawl %T", x)
+	}
+	if !c.Enter(0) {
+		t.Fatalf("could not enter embedded field")
+	}
+	if c.Kind() != reflect.Struct || c.Size() != 1 {
+		t.Fatalf("embedded struct must have one field")
+	}
+	if _, ok := c.Pointer(); ok {
+		t.Fatalf("embedded struct with unexported type must not be accessible")
+	}
+	if !c.Enter(0) {
+		t.Fatalf("could not enter field of embedded struct")
+	}
+	if _, ok := c.Pointer(); !ok {
+		t.Fatalf("exported field of embedded struct must be accessible")
+	}
+	c.ReturnTo(0)
+	if !c.Enter(1) {
+		t.Fatalf("could not enter unexported field")
+	}
+	if c.Size() != 0 {
+		t.Fatalf("unexported field must not be enterable")
+	}
+}
+
+func TestCrawlerAppendKey(t *testing.T) {
+	x := map[string][]int{`a/b`: {1}}
+	c, ok := NewCrawler(&x)
+	if !ok {
+		t.Fatalf("could not crawl %T", x)
+	}
+	if err := c.Into(`a/b`, 0); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := `a\/b`, string(c.AppendKey(nil, 0, false)); want != got {
+		t.Fatalf("want key %q, got %q", want, got)
+	}
+	if want, got := `0`, string(c.AppendKey(nil, 1, false)); want != got {
+		t.Fatalf("want key %q, got %q", want, got)
+	}
+	if c.Kind() != reflect.Int {
+		t.Fatalf("want kind int, got %s", c.Kind())
+	}
+}
@@ -1,6 +1,10 @@
 package walk
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
 
 // Iterator knows all keys for a Crawler node and can enter them sequentially.
 // It is only valid for the node it was retrieved at.
@@ -45,9 +49,11 @@ func (c *Crawler) Iterator() Iterator {
 			}
 		}
 		if c.val.Kind() == reflect.Map {
+			keys := c.val.MapKeys()
+			sortKeys(keys)
 			return &mapIterator{
 				Crawler: c,
-				keys:    c.val.MapKeys(),
+				keys:    keys,
 			}
 		}
 	}
@@ -101,3 +107,52 @@ func (i *mapIterator) EnterNext() bool {
 func (i *mapIterator) Reset() {
 	i.i = 0
 }
+
+// sortKeys sorts map keys of bool, string, integer or floating point kinds
+// by value and other keys by their default format to provide a deterministic
+// order. Package value sorts map keys in the same way when formatting maps,
+// using its own representations of other keys.
+func sortKeys(keys []reflect.Value) {
+	if len(keys) < 2 {
+		return
+	}
+	var less func(a, b reflect.Value) bool
+	switch keys[0].Kind() {
+	case reflect.Bool:
+		less = func(a, b reflect.Value) bool { return !a.Bool() && b.Bool() }
+	case reflect.String:
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float32, reflect.Float64:
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	default:
+		if !keys[0].CanInterface() {
+			return
+		}
+		byString := keysByString{keys: keys, strs: make([]string, len(keys))}
+		for i, key := range keys {
+			byString.strs[i] = fmt.Sprint(key.Interface())
+		}
+		sort.Sort(byString)
+		return
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return less(keys[i], keys[j])
+	})
+}
+
+// keysByString sorts keys by their representations in strs.
+type keysByString struct {
+	keys []reflect.Value
+	strs []string
+}
+
+func (k keysByString) Len() int           { return len(k.keys) }
+func (k keysByString) Less(i, j int) bool { return k.strs[i] < k.strs[j] }
+func (k keysByString) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.strs[i], k.strs[j] = k.strs[j], k.strs[i]
+}
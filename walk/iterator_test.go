@@ -1,6 +1,12 @@
 package walk
 
-import "testing"
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func testIterator(t *testing.T, ptr interface{}, size int) {
 	c, ok := NewCrawler(ptr)
@@ -98,3 +104,38 @@ func TestIteratorInvalid(t *testing.T) {
 	// must not panic
 	rit.Leave()
 }
+
+func TestMapIteratorSorted(t *testing.T) {
+	tests := []struct {
+		m    interface{}
+		want string
+	}{
+		{map[string]int{"c": 0, "a": 0, "d": 0, "b": 0}, "a b c d"},
+		{map[bool]int{true: 0, false: 0}, "false true"},
+		// other keys are sorted by their default format
+		{map[netip.Addr]int{
+			netip.MustParseAddr("10.0.0.3"): 0,
+			netip.MustParseAddr("10.0.0.1"): 0,
+			netip.MustParseAddr("10.0.0.2"): 0,
+		}, "10.0.0.1 10.0.0.2 10.0.0.3"},
+	}
+	for _, test := range tests {
+		ptr := reflect.New(reflect.TypeOf(test.m))
+		ptr.Elem().Set(reflect.ValueOf(test.m))
+		c, ok := NewCrawler(ptr.Interface())
+		if !ok {
+			t.Fatalf("could not crawl %T", test.m)
+		}
+		var keys []string
+		for it := c.Iterator(); it.HasNext(); {
+			if !it.EnterNext() {
+				t.Fatalf("could not enter map element")
+			}
+			keys = append(keys, fmt.Sprint(c.Key(0)))
+			c.Leave()
+		}
+		if got := strings.Join(keys, " "); got != test.want {
+			t.Errorf("%T: want keys %q, got %q", test.m, test.want, got)
+		}
+	}
+}